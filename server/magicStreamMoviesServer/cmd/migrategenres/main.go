@@ -0,0 +1,53 @@
+// Command migrategenres builds the genres collection from the genres embedded
+// in movies and users, merging duplicate spellings such as "Sci-fi" and
+// "Sci-Fi" into one canonical genre and rewriting the embedded copies.
+//
+//	go run ./cmd/migrategenres
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/config"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/database"
+)
+
+func main() {
+	cfg, err := config.Load()
+
+	if err != nil {
+		log.Fatal("Unable to load configuration: ", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	if err := database.Connect(ctx, cfg.Database); err != nil {
+		log.Fatal("Unable to connect to MongoDB: ", err)
+	}
+
+	defer database.Disconnect(context.Background())
+
+	report, err := mergeDuplicateGenres(ctx)
+
+	if err != nil {
+		log.Fatal("Genre migration failed: ", err)
+	}
+
+	fmt.Printf("%d genres, %d movies updated, %d users updated\n", len(report.Genres), report.MoviesUpdated, report.UsersUpdated)
+
+	for from, to := range report.Renamed {
+		fmt.Printf("  %q -> %q\n", from, to)
+	}
+
+	if len(report.Collisions) > 0 {
+		fmt.Printf("%d genre ids are shared by different genres and were not merged:\n", len(report.Collisions))
+
+		for _, collision := range report.Collisions {
+			fmt.Printf("  %d: %q\n", collision.GenreID, collision.GenreNames)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"sort"
+
+	controller "github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/controllers"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/database"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// The migration reads and rewrites the raw documents, including genres
+// embedded in movies and users, so it works on the collections directly.
+func genreCollection() *mongo.Collection { return database.OpenCollection("genres") }
+func movieCollection() *mongo.Collection { return database.OpenCollection("movies") }
+func userCollection() *mongo.Collection  { return database.OpenCollection("users") }
+
+// genreDocument is how a genre is stored in the genres collection
+func genreDocument(genre models.Genre) bson.M {
+	return bson.M{
+		"genre_id":   genre.GenreID,
+		"genre_name": genre.GenreName,
+		"name_key":   repository.GenreNameKey(genre.GenreName),
+	}
+}
+
+// mergeDuplicateGenres is the migration that makes the genres collection the
+// single source of genres. It groups every genre found in the genres
+// collection, movies and users by GenreNameKey, keeps one canonical genre per
+// group (the stored one if any, otherwise the lowest genre_id with its name
+// canonicalized), saves it to the genres collection and rewrites the genres
+// embedded in movies and users to the canonical id and name. Groups whose
+// canonical genre_id is already taken by another group are reported as
+// collisions and left untouched, since saving them would overwrite that genre.
+func mergeDuplicateGenres(ctx context.Context) (models.GenreMergeReport, error) {
+	report := models.GenreMergeReport{Renamed: map[string]string{}}
+
+	stored, err := collectGenres(ctx, genreCollection(), "", bson.M{})
+
+	if err != nil {
+		return report, err
+	}
+
+	embeddedMovies, err := collectGenres(ctx, movieCollection(), "genre", bson.M{"genre": bson.M{"$exists": true}})
+
+	if err != nil {
+		return report, err
+	}
+
+	embeddedUsers, err := collectGenres(ctx, userCollection(), "favourite_genres", bson.M{"favourite_genres": bson.M{"$exists": true}})
+
+	if err != nil {
+		return report, err
+	}
+
+	// pick the canonical genre of each spelling group
+	canonical := map[string]models.Genre{}
+
+	for _, genre := range stored {
+		key := repository.GenreNameKey(genre.GenreName)
+		if current, ok := canonical[key]; !ok || genre.GenreID < current.GenreID {
+			canonical[key] = genre
+		}
+	}
+
+	storedKeys := make(map[string]bool, len(canonical))
+	for key := range canonical {
+		storedKeys[key] = true
+	}
+
+	for _, genre := range append(embeddedMovies, embeddedUsers...) {
+		key := repository.GenreNameKey(genre.GenreName)
+		if key == "" || storedKeys[key] {
+			continue
+		}
+		if current, ok := canonical[key]; !ok || genre.GenreID < current.GenreID {
+			canonical[key] = models.Genre{GenreID: genre.GenreID, GenreName: controller.CanonicalGenreName(genre.GenreName)}
+		}
+	}
+
+	report.Collisions = removeGenreIdCollisions(canonical, storedKeys)
+
+	keys := make([]string, 0, len(canonical))
+	for key := range canonical {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		genre := canonical[key]
+
+		// drop other stored spellings of this genre, then upsert the canonical one
+		for _, other := range stored {
+			if other.GenreID == genre.GenreID || repository.GenreNameKey(other.GenreName) != key {
+				continue
+			}
+			if _, err := genreCollection().DeleteOne(ctx, bson.M{"genre_id": other.GenreID}); err != nil {
+				return report, err
+			}
+		}
+
+		if _, err := genreCollection().UpdateOne(ctx,
+			bson.M{"genre_id": genre.GenreID},
+			bson.M{"$set": genreDocument(genre)},
+			options.UpdateOne().SetUpsert(true),
+		); err != nil {
+			return report, err
+		}
+
+		report.Genres = append(report.Genres, genre)
+	}
+
+	for _, genre := range append(append(stored, embeddedMovies...), embeddedUsers...) {
+		if target, ok := canonical[repository.GenreNameKey(genre.GenreName)]; ok && target.GenreName != genre.GenreName {
+			report.Renamed[genre.GenreName] = target.GenreName
+		}
+	}
+
+	report.MoviesUpdated, err = rewriteEmbeddedGenres(ctx, movieCollection(), "genre", canonical)
+
+	if err != nil {
+		return report, err
+	}
+
+	report.UsersUpdated, err = rewriteEmbeddedGenres(ctx, userCollection(), "favourite_genres", canonical)
+
+	return report, err
+}
+
+// removeGenreIdCollisions finds spelling groups in canonical that share a
+// genre_id and removes them from canonical. A stored genre owns its id, so only
+// the groups colliding with it are removed; groups found only in movies and
+// users that collide with each other are all removed.
+func removeGenreIdCollisions(canonical map[string]models.Genre, storedKeys map[string]bool) []models.GenreCollision {
+	keysById := map[int][]string{}
+
+	for key, genre := range canonical {
+		keysById[genre.GenreID] = append(keysById[genre.GenreID], key)
+	}
+
+	ids := make([]int, 0, len(keysById))
+	for id := range keysById {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	collisions := []models.GenreCollision{}
+
+	for _, id := range ids {
+		keys := keysById[id]
+
+		if len(keys) < 2 {
+			continue
+		}
+
+		sort.Strings(keys)
+
+		collision := models.GenreCollision{GenreID: id}
+
+		for _, key := range keys {
+			collision.GenreNames = append(collision.GenreNames, canonical[key].GenreName)
+			if !storedKeys[key] {
+				delete(canonical, key)
+			}
+		}
+
+		collisions = append(collisions, collision)
+	}
+
+	return collisions
+}
+
+// collectGenres returns the genres stored in collection; when field is set
+// the genres are read from that embedded array instead of the documents.
+func collectGenres(ctx context.Context, collection *mongo.Collection, field string, filter bson.M) ([]models.Genre, error) {
+	if field == "" {
+		cursor, err := collection.Find(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		defer cursor.Close(ctx)
+
+		var genres []models.Genre
+		err = cursor.All(ctx, &genres)
+		return genres, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$unwind", Value: "$" + field}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"genre_id": "$" + field + ".genre_id", "genre_name": "$" + field + ".genre_name"}}}},
+		{{Key: "$replaceWith", Value: "$_id"}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var genres []models.Genre
+	err = cursor.All(ctx, &genres)
+
+	return genres, err
+}
+
+// rewriteEmbeddedGenres replaces every genre in the given array field with its
+// canonical version, removing duplicates, and returns how many documents changed.
+func rewriteEmbeddedGenres(ctx context.Context, collection *mongo.Collection, field string, canonical map[string]models.Genre) (int64, error) {
+	cursor, err := collection.Find(ctx, bson.M{field: bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{field: 1}))
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var updated int64
+
+	for cursor.Next(ctx) {
+		var doc bson.M
+
+		if err := cursor.Decode(&doc); err != nil {
+			return updated, err
+		}
+
+		var genres []models.Genre
+
+		if value, err := cursor.Current.LookupErr(field); err == nil {
+			if err := value.Unmarshal(&genres); err != nil {
+				return updated, err
+			}
+		}
+
+		rewritten := make([]models.Genre, 0, len(genres))
+		seen := map[int]bool{}
+		changed := false
+
+		for _, genre := range genres {
+			target, ok := canonical[repository.GenreNameKey(genre.GenreName)]
+			if !ok {
+				target = genre
+			}
+			if target != genre {
+				changed = true
+			}
+			if seen[target.GenreID] {
+				changed = true
+				continue
+			}
+			seen[target.GenreID] = true
+			rewritten = append(rewritten, target)
+		}
+
+		if !changed {
+			continue
+		}
+
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": doc["_id"]}, bson.M{"$set": bson.M{field: rewritten}}); err != nil {
+			return updated, err
+		}
+
+		updated++
+	}
+
+	return updated, cursor.Err()
+}
@@ -0,0 +1,87 @@
+// Command rerank re-classifies every movie's admin review against the current
+// rankings collection, e.g. after an admin added or renamed a ranking.
+//
+//	go run ./cmd/rerank -dry-run -concurrency 4
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/config"
+	controller "github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/controllers"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/database"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/jobs"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/ranker"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report ranking changes without writing them")
+	concurrency := flag.Int("concurrency", jobs.DefaultRerankConcurrency, "number of reviews ranked in parallel")
+	flag.Parse()
+
+	cfg, err := config.Load()
+
+	if err != nil {
+		log.Fatal("Unable to load configuration: ", err)
+	}
+
+	if err := database.Connect(context.Background(), cfg.Database); err != nil {
+		log.Fatal("Unable to connect to MongoDB: ", err)
+	}
+
+	defer database.Disconnect(context.Background())
+
+	reviewRanker, err := ranker.FromConfig(cfg)
+
+	if err != nil {
+		log.Fatal("Unable to configure review ranker: ", err)
+	}
+
+	repos := repository.NewMongoRepositories(database.OpenDatabase())
+
+	reranker := jobs.NewReranker(func(ctx context.Context, review string) (models.Ranking, error) {
+		name, value, err := controller.GetReviewRanking(ctx, reviewRanker, repos.Rankings, review)
+		return models.Ranking{RankingName: name, RankingValue: value}, err
+	}, repos.Movies, repos.RerankRuns)
+
+	// Stop cleanly on Ctrl+C; movies already processed keep their new ranking
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	run := jobs.NewRerankRun(*dryRun, *concurrency)
+
+	err = reranker.Run(ctx, &run, func(progress models.RerankRun) {
+		if progress.FinishedAt == nil {
+			fmt.Fprintf(os.Stderr, "\r%d/%d processed, %d changed, %d failed", progress.Processed, progress.Total, progress.Changed, progress.Failed)
+		}
+	})
+
+	fmt.Fprintln(os.Stderr)
+
+	mode := "applied"
+	if run.DryRun {
+		mode = "dry run, not applied"
+	}
+
+	fmt.Printf("%d movies processed, %d rankings changed (%s), %d failed\n", run.Processed, run.Changed, mode, run.Failed)
+
+	for _, diff := range run.Diffs {
+		fmt.Printf("  %-12s %-40s %s (%d) -> %s (%d)\n", diff.ImdbID, diff.Title,
+			diff.Old.RankingName, diff.Old.RankingValue, diff.New.RankingName, diff.New.RankingValue)
+	}
+
+	for _, failure := range run.Failures {
+		fmt.Printf("  %-12s FAILED: %s\n", failure.ImdbID, failure.Error)
+	}
+
+	if err != nil {
+		log.Fatal("Re-rank did not complete: ", err)
+	}
+}
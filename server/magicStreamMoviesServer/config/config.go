@@ -0,0 +1,297 @@
+// Package config loads the server settings once at startup from the
+// environment, an optional .env file and an optional YAML or TOML file.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+)
+
+type ServerConfig struct {
+	Port                     string `yaml:"port" toml:"port"`
+	ReadTimeoutSeconds       int    `yaml:"read_timeout_seconds" toml:"read_timeout_seconds"`
+	ReadHeaderTimeoutSeconds int    `yaml:"read_header_timeout_seconds" toml:"read_header_timeout_seconds"`
+	WriteTimeoutSeconds      int    `yaml:"write_timeout_seconds" toml:"write_timeout_seconds"`
+	IdleTimeoutSeconds       int    `yaml:"idle_timeout_seconds" toml:"idle_timeout_seconds"`
+	// ShutdownTimeoutSeconds bounds draining requests, stopping workers and disconnecting MongoDB
+	ShutdownTimeoutSeconds int `yaml:"shutdown_timeout_seconds" toml:"shutdown_timeout_seconds"`
+}
+
+type DatabaseConfig struct {
+	URI                           string `yaml:"uri" toml:"uri"`
+	Name                          string `yaml:"name" toml:"name"`
+	ConnectTimeoutSeconds         int    `yaml:"connect_timeout_seconds" toml:"connect_timeout_seconds"`
+	ServerSelectionTimeoutSeconds int    `yaml:"server_selection_timeout_seconds" toml:"server_selection_timeout_seconds"`
+	MaxPoolSize                   int    `yaml:"max_pool_size" toml:"max_pool_size"`
+	// ConnectAttempts is how many times the server tries to reach MongoDB on startup
+	ConnectAttempts int `yaml:"connect_attempts" toml:"connect_attempts"`
+}
+
+type AuthConfig struct {
+	SecretKey        string `yaml:"secret_key" toml:"secret_key"`
+	SecretRefreshKey string `yaml:"secret_refresh_key" toml:"secret_refresh_key"`
+	// RolePermissions grants or, prefixed with "-", revokes permissions on top of
+	// the default role/permission matrix, e.g. "USER=reviews:write,-history:write"
+	RolePermissions string `yaml:"role_permissions" toml:"role_permissions"`
+}
+
+// AdminConfig is the account created or promoted when no admin exists
+type AdminConfig struct {
+	Email    string `yaml:"email" toml:"email"`
+	Password string `yaml:"password" toml:"password"`
+}
+
+type OpenAIConfig struct {
+	APIKey         string `yaml:"api_key" toml:"api_key"`
+	Model          string `yaml:"model" toml:"model"`
+	EmbeddingModel string `yaml:"embedding_model" toml:"embedding_model"`
+}
+
+type OllamaConfig struct {
+	ServerURL      string `yaml:"server_url" toml:"server_url"`
+	Model          string `yaml:"model" toml:"model"`
+	EmbeddingModel string `yaml:"embedding_model" toml:"embedding_model"`
+}
+
+type RankerConfig struct {
+	// Provider is openai, ollama, lexicon or fake
+	Provider       string `yaml:"provider" toml:"provider"`
+	PromptTemplate string `yaml:"prompt_template" toml:"prompt_template"`
+	MaxRetries     int    `yaml:"max_retries" toml:"max_retries"`
+	Workers        int    `yaml:"workers" toml:"workers"`
+	MaxAttempts    int    `yaml:"max_attempts" toml:"max_attempts"`
+}
+
+type RecommenderConfig struct {
+	IntervalSeconds int `yaml:"interval_seconds" toml:"interval_seconds"`
+	Neighbours      int `yaml:"neighbours" toml:"neighbours"`
+	MovieLimit      int `yaml:"movie_limit" toml:"movie_limit"`
+}
+
+type EmbeddingConfig struct {
+	// Provider is local, openai or ollama
+	Provider            string `yaml:"provider" toml:"provider"`
+	SyncIntervalSeconds int    `yaml:"sync_interval_seconds" toml:"sync_interval_seconds"`
+}
+
+type Config struct {
+	Server      ServerConfig      `yaml:"server" toml:"server"`
+	Database    DatabaseConfig    `yaml:"database" toml:"database"`
+	Auth        AuthConfig        `yaml:"auth" toml:"auth"`
+	Admin       AdminConfig       `yaml:"admin" toml:"admin"`
+	OpenAI      OpenAIConfig      `yaml:"openai" toml:"openai"`
+	Ollama      OllamaConfig      `yaml:"ollama" toml:"ollama"`
+	Ranker      RankerConfig      `yaml:"ranker" toml:"ranker"`
+	Recommender RecommenderConfig `yaml:"recommender" toml:"recommender"`
+	Embedding   EmbeddingConfig   `yaml:"embedding" toml:"embedding"`
+}
+
+// Default returns the settings used when nothing overrides them
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:                     "8080",
+			ReadTimeoutSeconds:       30,
+			ReadHeaderTimeoutSeconds: 10,
+			WriteTimeoutSeconds:      120,
+			IdleTimeoutSeconds:       120,
+			ShutdownTimeoutSeconds:   30,
+		},
+		Database: DatabaseConfig{
+			ConnectTimeoutSeconds:         10,
+			ServerSelectionTimeoutSeconds: 5,
+			MaxPoolSize:                   100,
+			ConnectAttempts:               5,
+		},
+		Ranker: RankerConfig{
+			MaxRetries:  2,
+			Workers:     4,
+			MaxAttempts: 3,
+		},
+		Recommender: RecommenderConfig{
+			IntervalSeconds: 60,
+			Neighbours:      50,
+			MovieLimit:      5,
+		},
+		Embedding: EmbeddingConfig{SyncIntervalSeconds: 600},
+	}
+}
+
+// Load builds the configuration from, in increasing priority: the defaults,
+// the YAML or TOML file named by CONFIG_FILE, and the environment (including
+// the variables of the .env file). It does not check required values; see Validate.
+func Load() (*Config, error) {
+	if err := godotenv.Load(".env"); err != nil {
+		log.Println("Warning: .env file not found (using system env variables)")
+	}
+
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile decodes a .yaml, .yml or .toml file over cfg
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("config file %s must be .yaml, .yml or .toml", path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// applyEnv overrides cfg with every env variable that is set and not empty
+func applyEnv(cfg *Config) error {
+	stringVars := map[string]*string{
+		"PORT":                   &cfg.Server.Port,
+		"MONGODB_URI":            &cfg.Database.URI,
+		"DATABASE_NAME":          &cfg.Database.Name,
+		"SECRET_KEY":             &cfg.Auth.SecretKey,
+		"SECRET_REFRESH_KEY":     &cfg.Auth.SecretRefreshKey,
+		"ROLE_PERMISSIONS":       &cfg.Auth.RolePermissions,
+		"ADMIN_EMAIL":            &cfg.Admin.Email,
+		"ADMIN_PASSWORD":         &cfg.Admin.Password,
+		"OPENAI_API_KEY":         &cfg.OpenAI.APIKey,
+		"OPENAI_MODEL":           &cfg.OpenAI.Model,
+		"OPENAI_EMBEDDING_MODEL": &cfg.OpenAI.EmbeddingModel,
+		"OLLAMA_SERVER_URL":      &cfg.Ollama.ServerURL,
+		"OLLAMA_MODEL":           &cfg.Ollama.Model,
+		"OLLAMA_EMBEDDING_MODEL": &cfg.Ollama.EmbeddingModel,
+		"REVIEW_RANKER":          &cfg.Ranker.Provider,
+		"BASE_PROMPT_TEMPLATE":   &cfg.Ranker.PromptTemplate,
+		"EMBEDDING_PROVIDER":     &cfg.Embedding.Provider,
+	}
+
+	for key, field := range stringVars {
+		if val := os.Getenv(key); val != "" {
+			*field = val
+		}
+	}
+
+	intVars := map[string]*int{
+		"HTTP_READ_TIMEOUT_SECONDS":                &cfg.Server.ReadTimeoutSeconds,
+		"HTTP_READ_HEADER_TIMEOUT_SECONDS":         &cfg.Server.ReadHeaderTimeoutSeconds,
+		"HTTP_WRITE_TIMEOUT_SECONDS":               &cfg.Server.WriteTimeoutSeconds,
+		"HTTP_IDLE_TIMEOUT_SECONDS":                &cfg.Server.IdleTimeoutSeconds,
+		"SHUTDOWN_TIMEOUT_SECONDS":                 &cfg.Server.ShutdownTimeoutSeconds,
+		"MONGODB_CONNECT_TIMEOUT_SECONDS":          &cfg.Database.ConnectTimeoutSeconds,
+		"MONGODB_SERVER_SELECTION_TIMEOUT_SECONDS": &cfg.Database.ServerSelectionTimeoutSeconds,
+		"MONGODB_MAX_POOL_SIZE":                    &cfg.Database.MaxPoolSize,
+		"MONGODB_CONNECT_ATTEMPTS":                 &cfg.Database.ConnectAttempts,
+		"RANKER_MAX_RETRIES":                       &cfg.Ranker.MaxRetries,
+		"RANKING_WORKERS":                          &cfg.Ranker.Workers,
+		"RANKING_MAX_ATTEMPTS":                     &cfg.Ranker.MaxAttempts,
+		"RECOMMENDER_INTERVAL_SECONDS":             &cfg.Recommender.IntervalSeconds,
+		"RECOMMENDER_NEIGHBOURS":                   &cfg.Recommender.Neighbours,
+		"RECOMMENDED_MOVIE_LIMIT":                  &cfg.Recommender.MovieLimit,
+		"EMBEDDING_SYNC_INTERVAL_SECONDS":          &cfg.Embedding.SyncIntervalSeconds,
+	}
+
+	for key, field := range intVars {
+		val := os.Getenv(key)
+
+		if val == "" {
+			continue
+		}
+
+		parsed, err := strconv.Atoi(val)
+
+		if err != nil {
+			return fmt.Errorf("%s must be an integer, got %q", key, val)
+		}
+
+		*field = parsed
+	}
+
+	return nil
+}
+
+// Validate checks the values the server cannot start without
+func (c *Config) Validate() error {
+	var problems []string
+
+	if c.Server.Port == "" {
+		problems = append(problems, "PORT must not be empty")
+	}
+	if c.Database.URI == "" {
+		problems = append(problems, "MONGODB_URI must be set")
+	}
+	if c.Database.Name == "" {
+		problems = append(problems, "DATABASE_NAME must be set")
+	}
+	if c.Auth.SecretKey == "" {
+		problems = append(problems, "SECRET_KEY must be set")
+	}
+	if c.Auth.SecretRefreshKey == "" {
+		problems = append(problems, "SECRET_REFRESH_KEY must be set")
+	}
+
+	positive := map[string]int{
+		"HTTP_READ_TIMEOUT_SECONDS":                c.Server.ReadTimeoutSeconds,
+		"HTTP_READ_HEADER_TIMEOUT_SECONDS":         c.Server.ReadHeaderTimeoutSeconds,
+		"HTTP_WRITE_TIMEOUT_SECONDS":               c.Server.WriteTimeoutSeconds,
+		"HTTP_IDLE_TIMEOUT_SECONDS":                c.Server.IdleTimeoutSeconds,
+		"SHUTDOWN_TIMEOUT_SECONDS":                 c.Server.ShutdownTimeoutSeconds,
+		"MONGODB_CONNECT_TIMEOUT_SECONDS":          c.Database.ConnectTimeoutSeconds,
+		"MONGODB_SERVER_SELECTION_TIMEOUT_SECONDS": c.Database.ServerSelectionTimeoutSeconds,
+		"MONGODB_MAX_POOL_SIZE":                    c.Database.MaxPoolSize,
+		"MONGODB_CONNECT_ATTEMPTS":                 c.Database.ConnectAttempts,
+		"RANKING_WORKERS":                          c.Ranker.Workers,
+		"RANKING_MAX_ATTEMPTS":                     c.Ranker.MaxAttempts,
+		"RECOMMENDER_INTERVAL_SECONDS":             c.Recommender.IntervalSeconds,
+		"RECOMMENDER_NEIGHBOURS":                   c.Recommender.Neighbours,
+		"RECOMMENDED_MOVIE_LIMIT":                  c.Recommender.MovieLimit,
+		"EMBEDDING_SYNC_INTERVAL_SECONDS":          c.Embedding.SyncIntervalSeconds,
+	}
+
+	for key, val := range positive {
+		if val <= 0 {
+			problems = append(problems, key+" must be positive")
+		}
+	}
+
+	if c.Ranker.MaxRetries < 0 {
+		problems = append(problems, "RANKER_MAX_RETRIES must not be negative")
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return errors.New("invalid configuration: " + strings.Join(problems, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,303 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/config"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// userSummary is the admin view of user, without the password hash or stored tokens
+func userSummary(user models.User) models.UserSummary {
+	return models.UserSummary{
+		UserID:          user.UserID,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		Email:           user.Email,
+		Role:            user.Role,
+		Disabled:        user.Disabled,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+		FavouriteGenres: user.FavouriteGenres,
+	}
+}
+
+// rejectSelfModification stops an admin from demoting, disabling or deleting
+// their own account, which could otherwise leave the system without an admin.
+func rejectSelfModification(c *gin.Context, targetUserId string) bool {
+	userId, err := utils.GetUserIdFromContext(c)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found in this context"})
+		return true
+	}
+
+	if userId == targetUserId {
+		c.JSON(http.StatusConflict, gin.H{"error": "Admins cannot modify their own account"})
+		return true
+	}
+
+	return false
+}
+
+// ListUsers handles GET /admin/users requests
+// It returns a page of users, optionally filtered by role, without credentials.
+func ListUsers(users repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		page, limit, ok := pageParams(c)
+
+		if !ok {
+			return
+		}
+
+		query := repository.UserQuery{
+			Role:  c.Query("role"),
+			Skip:  (page - 1) * limit,
+			Limit: limit,
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		total, err := users.Count(ctx, query)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count users"})
+			return
+		}
+
+		found, err := users.List(ctx, query)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+			return
+		}
+
+		summaries := make([]models.UserSummary, 0, len(found))
+
+		for _, user := range found {
+			summaries = append(summaries, userSummary(user))
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"users": summaries,
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		})
+	}
+}
+
+// GetUser handles GET /admin/users/:user_id requests
+func GetUser(users repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId := c.Param("user_id")
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		user, err := users.Get(ctx, userId)
+
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+			return
+		}
+
+		c.JSON(http.StatusOK, userSummary(user))
+	}
+}
+
+// UpdateUserRole handles PATCH /admin/users/:user_id/role requests
+// Existing tokens are revoked so the new role takes effect on the next login.
+func UpdateUserRole(users repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId := c.Param("user_id")
+
+		if rejectSelfModification(c, userId) {
+			return
+		}
+
+		var req models.UserRoleUpdate
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+			return
+		}
+
+		if err := validate.Struct(req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "role must be one of ADMIN USER"})
+			return
+		}
+
+		updateUser(c, users, userId, repository.UserUpdate{Role: &req.Role})
+	}
+}
+
+// DisableUser handles POST /admin/users/:user_id/disable requests
+func DisableUser(users repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId := c.Param("user_id")
+
+		if rejectSelfModification(c, userId) {
+			return
+		}
+
+		disabled := true
+
+		updateUser(c, users, userId, repository.UserUpdate{Disabled: &disabled})
+	}
+}
+
+// EnableUser handles POST /admin/users/:user_id/enable requests
+func EnableUser(users repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		disabled := false
+
+		updateUser(c, users, c.Param("user_id"), repository.UserUpdate{Disabled: &disabled})
+	}
+}
+
+// updateUser applies update to a user, revokes their tokens and
+// writes the updated admin view of the user as the response.
+func updateUser(c *gin.Context, users repository.UserRepository, userId string, update repository.UserUpdate) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+	defer cancel()
+
+	user, err := users.Update(ctx, userId, update)
+
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		return
+	}
+
+	if err := utils.RevokeAllUserTokens(userId); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "User updated but failed to revoke tokens"})
+		return
+	}
+
+	c.JSON(http.StatusOK, userSummary(user))
+}
+
+// DeleteUser handles DELETE /admin/users/:user_id requests
+func DeleteUser(users repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId := c.Param("user_id")
+
+		if rejectSelfModification(c, userId) {
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		// Revoke first so the user's outstanding tokens stop working even if
+		// the document is already gone by the time they are presented.
+		if err := utils.RevokeAllUserTokens(userId); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke tokens"})
+			return
+		}
+
+		if err := users.Delete(ctx, userId); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// BootstrapAdmin makes sure at least one ADMIN account exists.
+// When no admin is present it promotes the user with the admin email, or creates
+// that account using the admin password if it does not exist yet. It does nothing
+// when an admin already exists or ADMIN_EMAIL is not set.
+func BootstrapAdmin(users repository.UserRepository, admin config.AdminConfig) error {
+	email := admin.Email
+
+	if email == "" {
+		return nil
+	}
+
+	var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+	defer cancel()
+
+	count, err := users.Count(ctx, repository.UserQuery{Role: "ADMIN"})
+
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	role, disabled := "ADMIN", false
+
+	_, err = users.UpdateByEmail(ctx, email, repository.UserUpdate{Role: &role, Disabled: &disabled})
+
+	if err == nil {
+		log.Println("Promoted existing user to ADMIN:", email)
+		return nil
+	}
+
+	if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	password := admin.Password
+
+	if len(password) < 6 {
+		return errors.New("ADMIN_PASSWORD must be set (min 6 characters) to create the first admin")
+	}
+
+	hashedPassword, err := HashPassword(password)
+
+	if err != nil {
+		return err
+	}
+
+	account := models.User{
+		UserID:          bson.NewObjectID().Hex(),
+		FirstName:       "Admin",
+		LastName:        "User",
+		Email:           email,
+		Password:        hashedPassword,
+		Role:            "ADMIN",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+		FavouriteGenres: []models.Genre{},
+	}
+
+	if err := users.Insert(ctx, &account); err != nil {
+		return err
+	}
+
+	log.Println("Created first ADMIN account:", email)
+
+	return nil
+}
@@ -0,0 +1,286 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/embedder"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// errInvalidGenre marks references to genres that do not exist or are misspelled
+var errInvalidGenre = errors.New("invalid genre")
+
+// CanonicalGenreName normalizes the spelling of a genre name: surrounding and
+// repeated whitespace is removed and every word, including each part of a
+// hyphenated word, is capitalized ("  sci-fi " becomes "Sci-Fi").
+func CanonicalGenreName(name string) string {
+	words := strings.Fields(name)
+
+	for i, word := range words {
+		runes := []rune(strings.ToLower(word))
+		capitalize := true
+
+		for j, r := range runes {
+			if capitalize && unicode.IsLetter(r) {
+				runes[j] = unicode.ToUpper(r)
+			}
+			capitalize = r == '-' || r == '/'
+		}
+
+		words[i] = string(runes)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// resolveGenres checks that every referenced genre exists in the genre
+// repository and returns the canonical genres, without duplicates. A genre
+// is referenced by genre_id; its genre_name, if given, must match the stored
+// one up to spelling differences.
+func resolveGenres(ctx context.Context, genreRepo repository.GenreRepository, genres []models.Genre) ([]models.Genre, error) {
+	ids := make([]int, 0, len(genres))
+
+	for _, genre := range genres {
+		ids = append(ids, genre.GenreID)
+	}
+
+	stored, err := genreRepo.GetMany(ctx, ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[int]models.Genre, len(stored))
+
+	for _, genre := range stored {
+		byId[genre.GenreID] = genre
+	}
+
+	resolved := make([]models.Genre, 0, len(genres))
+	seen := map[int]bool{}
+
+	for _, genre := range genres {
+		canonical, ok := byId[genre.GenreID]
+
+		if !ok {
+			return nil, fmt.Errorf("%w: genre_id %d does not exist", errInvalidGenre, genre.GenreID)
+		}
+
+		if genre.GenreName != "" && repository.GenreNameKey(genre.GenreName) != repository.GenreNameKey(canonical.GenreName) {
+			return nil, fmt.Errorf("%w: genre_id %d is %q, not %q", errInvalidGenre, genre.GenreID, canonical.GenreName, genre.GenreName)
+		}
+
+		if !seen[canonical.GenreID] {
+			seen[canonical.GenreID] = true
+			resolved = append(resolved, canonical)
+		}
+	}
+
+	return resolved, nil
+}
+
+// checkGenres resolves genres or responds with 400 when a genre does not exist
+func checkGenres(c *gin.Context, ctx context.Context, genreRepo repository.GenreRepository, genres []models.Genre) ([]models.Genre, bool) {
+	resolved, err := resolveGenres(ctx, genreRepo, genres)
+
+	if err != nil {
+		if errors.Is(err, errInvalidGenre) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check genres"})
+		return nil, false
+	}
+
+	return resolved, true
+}
+
+// genreIdParam parses the :genre_id path parameter
+func genreIdParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("genre_id"))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "genre_id must be an integer"})
+		return 0, false
+	}
+
+	return id, true
+}
+
+// GetGenres handles GET /genres requests
+func GetGenres(genreRepo repository.GenreRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		genres, err := genreRepo.List(ctx)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch genres"})
+			return
+		}
+
+		c.JSON(http.StatusOK, genres)
+	}
+}
+
+// CreateGenre handles POST /genres requests
+// The name is stored in its canonical spelling; a name that differs from an
+// existing genre only in spelling is rejected with 409.
+func CreateGenre(genreRepo repository.GenreRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var input models.GenreInput
+
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+
+		if err := validate.Struct(input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrorMessages(err)})
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		genre := models.Genre{GenreID: input.GenreID, GenreName: CanonicalGenreName(input.GenreName)}
+
+		if genre.GenreID == 0 {
+			nextId, err := genreRepo.NextID(ctx)
+
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign genre_id"})
+				return
+			}
+
+			genre.GenreID = nextId
+		}
+
+		if err := genreRepo.Insert(ctx, genre); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.JSON(http.StatusConflict, gin.H{"error": "A genre with this id or name already exists"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create genre"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, genre)
+	}
+}
+
+// UpdateGenre handles PUT /genres/:genre_id requests
+// Renaming a genre also renames the copies embedded in movies and users.
+func UpdateGenre(genreRepo repository.GenreRepository, movies repository.MovieRepository, users repository.UserRepository, similarIndex *embedder.Index) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id, ok := genreIdParam(c)
+
+		if !ok {
+			return
+		}
+
+		var input models.GenreInput
+
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+
+		if err := validate.Struct(input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrorMessages(err)})
+			return
+		}
+
+		genre := models.Genre{GenreID: id, GenreName: CanonicalGenreName(input.GenreName)}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		if err := genreRepo.Update(ctx, genre); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Genre not found"})
+				return
+			}
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.JSON(http.StatusConflict, gin.H{"error": "A genre with this name already exists"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update genre"})
+			return
+		}
+
+		// Rename the genre name copied into movies and users
+		if err := movies.RenameGenre(ctx, genre); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Genre updated but movies and users could not be updated"})
+			return
+		}
+
+		if err := users.RenameGenre(ctx, genre); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Genre updated but movies and users could not be updated"})
+			return
+		}
+
+		markMovieTextChanged(similarIndex)
+
+		c.JSON(http.StatusOK, genre)
+	}
+}
+
+// DeleteGenre handles DELETE /genres/:genre_id requests
+// A genre still referenced by a movie or a user cannot be deleted.
+func DeleteGenre(genreRepo repository.GenreRepository, movies repository.MovieRepository, users repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id, ok := genreIdParam(c)
+
+		if !ok {
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		movieCount, err := movies.CountWithGenre(ctx, id)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check genre usage"})
+			return
+		}
+
+		userCount, err := users.CountWithGenre(ctx, id)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check genre usage"})
+			return
+		}
+
+		if movieCount > 0 || userCount > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Genre is used by %d movies and %d users", movieCount, userCount)})
+			return
+		}
+
+		if err := genreRepo.Delete(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Genre not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete genre"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+func TestCanonicalGenreName(t *testing.T) {
+	tests := map[string]string{
+		"  sci-fi ":         "Sci-Fi",
+		"ACTION":            "Action",
+		"romantic   comedy": "Romantic Comedy",
+		"film/noir":         "Film/Noir",
+	}
+
+	for name, want := range tests {
+		if got := CanonicalGenreName(name); got != want {
+			t.Errorf("CanonicalGenreName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestCreateGenre(t *testing.T) {
+	repos := newTestRepos()
+	router := newTestRouter()
+	router.POST("/genres", CreateGenre(repos.Genres))
+
+	w := serve(t, router, http.MethodPost, "/genres", models.GenreInput{GenreName: "  science fiction "})
+	expectStatus(t, w, http.StatusCreated)
+
+	var genre models.Genre
+	decode(t, w, &genre)
+
+	if genre.GenreID != 3 || genre.GenreName != "Science Fiction" {
+		t.Fatalf("created %+v, want genre 3 Science Fiction", genre)
+	}
+
+	// differs from an existing genre only in spelling
+	w = serve(t, router, http.MethodPost, "/genres", models.GenreInput{GenreName: "DRAMA"})
+	expectStatus(t, w, http.StatusConflict)
+
+	w = serve(t, router, http.MethodPost, "/genres", models.GenreInput{GenreID: 1, GenreName: "Thriller"})
+	expectStatus(t, w, http.StatusConflict)
+}
+
+func TestUpdateGenreRenamesEmbeddedCopies(t *testing.T) {
+	repos := newTestRepos()
+	addTestMovie(t, repos.Movies, "tt1", "Heat", testAction)
+
+	router := newTestRouter()
+	router.PUT("/genres/:genre_id", UpdateGenre(repos.Genres, repos.Movies, repos.Users, nil))
+
+	w := serve(t, router, http.MethodPut, "/genres/1", models.GenreInput{GenreName: "action thriller"})
+	expectStatus(t, w, http.StatusOK)
+
+	movie, err := repos.Movies.Get(context.Background(), "tt1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := movie.Genre[0].GenreName; got != "Action Thriller" {
+		t.Fatalf("movie genre = %q, want Action Thriller", got)
+	}
+
+	w = serve(t, router, http.MethodPut, "/genres/9", models.GenreInput{GenreName: "Western"})
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+func TestDeleteGenreInUse(t *testing.T) {
+	repos := newTestRepos()
+	addTestMovie(t, repos.Movies, "tt1", "Heat", testAction)
+
+	router := newTestRouter()
+	router.DELETE("/genres/:genre_id", DeleteGenre(repos.Genres, repos.Movies, repos.Users))
+
+	expectStatus(t, serve(t, router, http.MethodDelete, "/genres/1", nil), http.StatusConflict)
+	expectStatus(t, serve(t, router, http.MethodDelete, "/genres/2", nil), http.StatusNoContent)
+	expectStatus(t, serve(t, router, http.MethodDelete, "/genres/2", nil), http.StatusNotFound)
+}
+
+func TestResolveGenres(t *testing.T) {
+	repos := newTestRepos()
+
+	resolved, err := resolveGenres(context.Background(), repos.Genres, []models.Genre{
+		{GenreID: 2, GenreName: "drama"},
+		{GenreID: 1},
+		{GenreID: 2},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resolved) != 2 || resolved[0] != testDrama || resolved[1] != testAction {
+		t.Fatalf("resolved %+v, want Drama and Action once each", resolved)
+	}
+
+	if _, err := resolveGenres(context.Background(), repos.Genres, []models.Genre{{GenreID: 1, GenreName: "Drama"}}); err == nil {
+		t.Fatal("expected a mismatched genre_name to be rejected")
+	}
+
+	if _, err := resolveGenres(context.Background(), repos.Genres, []models.Genre{{GenreID: 7}}); err == nil {
+		t.Fatal("expected an unknown genre_id to be rejected")
+	}
+}
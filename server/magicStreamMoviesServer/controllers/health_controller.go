@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/ranker"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds all dependency checks of one /readyz request
+const readinessTimeout = 5 * time.Second
+
+// dependencyStatus turns the error of a check into its reported status
+func dependencyStatus(err error) models.DependencyStatus {
+	if err != nil {
+		return models.DependencyStatus{Status: models.HealthStatusUnavailable, Error: err.Error()}
+	}
+
+	return models.DependencyStatus{Status: models.HealthStatusOK}
+}
+
+// checkRankings fails when the ranking scale cannot be read or is empty,
+// since admin reviews cannot be ranked without it
+func checkRankings(ctx context.Context, rankings repository.RankingRepository) error {
+	scale, err := rankings.List(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if len(scale) == 0 {
+		return errors.New("rankings collection is empty")
+	}
+
+	return nil
+}
+
+// checkReviewRanker fails when no review ranker is configured or when it is
+// the fake ranker, which must not serve real traffic
+func checkReviewRanker(reviewRanker ranker.ReviewRanker) error {
+	if reviewRanker == nil {
+		return errors.New("review ranker is not configured")
+	}
+
+	if reviewRanker.Provider() == ranker.ProviderFake {
+		return errors.New("the fake review ranker is for tests only")
+	}
+
+	return nil
+}
+
+// reviewRankerStatus reports the review ranker check with the provider name
+func reviewRankerStatus(reviewRanker ranker.ReviewRanker) models.DependencyStatus {
+	status := dependencyStatus(checkReviewRanker(reviewRanker))
+
+	if reviewRanker != nil {
+		status.Provider = reviewRanker.Provider()
+	}
+
+	return status
+}
+
+// HealthCheck handles GET /healthz requests
+// It only reports that the process is alive and serving requests.
+func HealthCheck() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		c.JSON(http.StatusOK, gin.H{"status": models.HealthStatusOK})
+	}
+}
+
+// ReadinessCheck handles GET /readyz requests
+// It checks that MongoDB answers ping, that the ranking scale is not empty and
+// that a review ranker other than the fake one is configured, responding 503
+// if any of them fails. The llm check names the ranker provider.
+func ReadinessCheck(ping func(ctx context.Context) error, rankings repository.RankingRepository, reviewRanker ranker.ReviewRanker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var ctx, cancel = context.WithTimeout(context.Background(), readinessTimeout)
+		defer cancel()
+
+		report := models.ReadinessReport{
+			Status: models.HealthStatusOK,
+			Checks: map[string]models.DependencyStatus{},
+		}
+
+		report.Checks["mongodb"] = dependencyStatus(ping(ctx))
+
+		report.Checks["rankings"] = dependencyStatus(checkRankings(ctx, rankings))
+
+		report.Checks["llm"] = reviewRankerStatus(reviewRanker)
+
+		for _, check := range report.Checks {
+			if check.Status != models.HealthStatusOK {
+				report.Status = models.HealthStatusUnavailable
+			}
+		}
+
+		if report.Status != models.HealthStatusOK {
+			c.JSON(http.StatusServiceUnavailable, report)
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/ranker"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+)
+
+func TestReviewRankerStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		reviewRanker ranker.ReviewRanker
+		status       string
+		provider     string
+	}{
+		{"not configured", nil, models.HealthStatusUnavailable, ""},
+		{"fake", &ranker.FakeRanker{}, models.HealthStatusUnavailable, ranker.ProviderFake},
+		{"lexicon", ranker.NewLexiconRanker(), models.HealthStatusOK, ranker.ProviderLexicon},
+	}
+
+	for _, tt := range tests {
+		got := reviewRankerStatus(tt.reviewRanker)
+
+		if got.Status != tt.status || got.Provider != tt.provider {
+			t.Errorf("%s: status = %+v, want %s from provider %q", tt.name, got, tt.status, tt.provider)
+		}
+
+		if tt.status != models.HealthStatusOK && got.Error == "" {
+			t.Errorf("%s: expected an error explaining why the ranker is not ready", tt.name)
+		}
+	}
+}
+
+func TestReadinessCheck(t *testing.T) {
+	rankings := repository.NewMemoryRankingRepository(models.Ranking{RankingValue: 1, RankingName: "Excellent"})
+	reviewRanker := ranker.NewLexiconRanker()
+
+	var pingErr error
+	ping := func(ctx context.Context) error { return pingErr }
+
+	router := newTestRouter()
+	router.GET("/readyz", ReadinessCheck(ping, rankings, reviewRanker))
+
+	expectStatus(t, serve(t, router, http.MethodGet, "/readyz", nil), http.StatusOK)
+
+	pingErr = errors.New("connection refused")
+
+	w := serve(t, router, http.MethodGet, "/readyz", nil)
+	expectStatus(t, w, http.StatusServiceUnavailable)
+
+	var report models.ReadinessReport
+	decode(t, w, &report)
+
+	if report.Checks["mongodb"].Status != models.HealthStatusUnavailable || report.Checks["rankings"].Status != models.HealthStatusOK {
+		t.Fatalf("report = %+v, want only mongodb unavailable", report)
+	}
+}
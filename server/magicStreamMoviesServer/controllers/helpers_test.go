@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const testUserId = "user-1"
+
+var (
+	testAction = models.Genre{GenreID: 1, GenreName: "Action"}
+	testDrama  = models.Genre{GenreID: 2, GenreName: "Drama"}
+)
+
+// newTestRepos creates in-memory repositories holding the test genres
+func newTestRepos() *repository.Repositories {
+	repos := repository.NewMemoryRepositories()
+	repos.Genres = repository.NewMemoryGenreRepository(testAction, testDrama)
+
+	return repos
+}
+
+// newTestRouter creates a router that authenticates every request as testUserId
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userId", testUserId)
+		c.Next()
+	})
+
+	return router
+}
+
+// serve sends a request with body encoded as JSON, when not nil, and returns the recorded response
+func serve(t *testing.T, router *gin.Engine, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var payload bytes.Buffer
+
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+// expectStatus fails the test when the response does not have status
+func expectStatus(t *testing.T, w *httptest.ResponseRecorder, status int) {
+	t.Helper()
+
+	if w.Code != status {
+		t.Fatalf("status = %d, want %d: %s", w.Code, status, w.Body.String())
+	}
+}
+
+// decode unmarshals the JSON response body into v
+func decode(t *testing.T, w *httptest.ResponseRecorder, v any) {
+	t.Helper()
+
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode %s: %v", w.Body.String(), err)
+	}
+}
+
+// addTestMovie stores a movie with the given imdb_id and title
+func addTestMovie(t *testing.T, movies repository.MovieRepository, imdbId, title string, genres ...models.Genre) {
+	t.Helper()
+
+	movie := &models.Movie{ImdbID: imdbId, Title: title, Genre: genres}
+
+	if err := movies.Insert(context.Background(), movie); err != nil {
+		t.Fatal(err)
+	}
+}
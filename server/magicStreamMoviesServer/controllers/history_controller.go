@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/recommend"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// completedProgress is the fraction of a title after which it counts as watched
+	completedProgress = 0.9
+	// continueWatchingMinProgress keeps titles that were only opened out of "continue watching"
+	continueWatchingMinProgress = 0.05
+)
+
+// RecordPlayback handles POST /me/history requests
+// It stores the latest playback position of a movie for the authenticated user.
+func RecordPlayback(movies repository.MovieRepository, history repository.HistoryRepository, recommender *recommend.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := utils.GetUserIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found in this context"})
+			return
+		}
+
+		var event models.PlaybackEvent
+
+		if err := c.ShouldBindJSON(&event); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+
+		if err := validate.Struct(event); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrorMessages(err)})
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		exists, err := movieExists(ctx, movies, event.ImdbID)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch movie"})
+			return
+		}
+
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
+			return
+		}
+
+		progress := event.Position / event.Duration
+
+		watched, err := history.Record(ctx, models.WatchProgress{
+			UserID:    userId,
+			ImdbID:    event.ImdbID,
+			Position:  event.Position,
+			Duration:  event.Duration,
+			Progress:  progress,
+			Completed: progress >= completedProgress,
+			UpdatedAt: time.Now(),
+		})
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record playback"})
+			return
+		}
+
+		markFeedbackChanged(recommender, userId)
+
+		c.JSON(http.StatusOK, watched)
+	}
+}
+
+// GetWatchHistory handles GET /me/history requests
+// It returns a page of the titles the user watched, most recent first.
+func GetWatchHistory(history repository.HistoryRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := utils.GetUserIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found in this context"})
+			return
+		}
+
+		page, limit, ok := pageParams(c)
+
+		if !ok {
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		query := repository.HistoryQuery{UserID: userId, Skip: (page - 1) * limit, Limit: limit}
+
+		total, err := history.Count(ctx, query)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count watch history"})
+			return
+		}
+
+		entries, err := history.List(ctx, query)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch watch history"})
+			return
+		}
+
+		c.JSON(http.StatusOK, models.HistoryPage{History: entries, Total: total, Page: page, Limit: limit})
+	}
+}
+
+// GetContinueWatching handles GET /me/continue-watching requests
+// It returns the partially watched titles, most recently watched first.
+func GetContinueWatching(history repository.HistoryRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := utils.GetUserIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found in this context"})
+			return
+		}
+
+		limit, err := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 64)
+
+		if err != nil || limit < 1 || limit > 50 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 50"})
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		entries, err := history.List(ctx, repository.HistoryQuery{
+			UserID:      userId,
+			Unfinished:  true,
+			MinProgress: continueWatchingMinProgress,
+			Limit:       limit,
+		})
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch continue watching"})
+			return
+		}
+
+		c.JSON(http.StatusOK, entries)
+	}
+}
+
+// DeleteHistoryEntry handles DELETE /me/history/:imdb_id requests
+func DeleteHistoryEntry(history repository.HistoryRepository, recommender *recommend.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := utils.GetUserIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found in this context"})
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		if err := history.Delete(ctx, userId, c.Param("imdb_id")); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Movie is not in your watch history"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete history entry"})
+			return
+		}
+
+		markFeedbackChanged(recommender, userId)
+
+		c.Status(http.StatusNoContent)
+	}
+}
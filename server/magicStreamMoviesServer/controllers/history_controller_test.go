@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+func TestWatchHistory(t *testing.T) {
+	repos := newTestRepos()
+	addTestMovie(t, repos.Movies, "tt1", "Heat")
+	addTestMovie(t, repos.Movies, "tt2", "Ronin")
+	addTestMovie(t, repos.Movies, "tt3", "Collateral")
+
+	router := newTestRouter()
+	router.POST("/me/history", RecordPlayback(repos.Movies, repos.History, nil))
+	router.GET("/me/history", GetWatchHistory(repos.History))
+	router.GET("/me/continue-watching", GetContinueWatching(repos.History))
+	router.DELETE("/me/history/:imdb_id", DeleteHistoryEntry(repos.History, nil))
+
+	events := []models.PlaybackEvent{
+		{ImdbID: "tt1", Position: 50, Duration: 100},
+		{ImdbID: "tt2", Position: 95, Duration: 100},
+		// only opened, too little to continue watching
+		{ImdbID: "tt3", Position: 1, Duration: 100},
+	}
+
+	for _, event := range events {
+		expectStatus(t, serve(t, router, http.MethodPost, "/me/history", event), http.StatusOK)
+	}
+
+	expectStatus(t, serve(t, router, http.MethodPost, "/me/history", models.PlaybackEvent{ImdbID: "tt9", Position: 1, Duration: 2}), http.StatusNotFound)
+	expectStatus(t, serve(t, router, http.MethodPost, "/me/history", models.PlaybackEvent{ImdbID: "tt1", Position: 3, Duration: 2}), http.StatusBadRequest)
+
+	// playing tt1 again updates its entry instead of adding one
+	w := serve(t, router, http.MethodPost, "/me/history", models.PlaybackEvent{ImdbID: "tt1", Position: 60, Duration: 100})
+	expectStatus(t, w, http.StatusOK)
+
+	var watched models.WatchProgress
+	decode(t, w, &watched)
+
+	if watched.Progress != 0.6 || watched.Completed || watched.StartedAt.IsZero() {
+		t.Fatalf("recorded %+v, want progress 0.6 and not completed", watched)
+	}
+
+	w = serve(t, router, http.MethodGet, "/me/history?limit=2", nil)
+	expectStatus(t, w, http.StatusOK)
+
+	var page models.HistoryPage
+	decode(t, w, &page)
+
+	if page.Total != 3 || len(page.History) != 2 || page.History[0].ImdbID != "tt1" {
+		t.Fatalf("history page = %+v, want 3 entries with tt1 first", page)
+	}
+
+	w = serve(t, router, http.MethodGet, "/me/continue-watching", nil)
+	expectStatus(t, w, http.StatusOK)
+
+	var unfinished []models.HistoryEntry
+	decode(t, w, &unfinished)
+
+	if len(unfinished) != 1 || unfinished[0].ImdbID != "tt1" || unfinished[0].Movie.Title != "Heat" {
+		t.Fatalf("continue watching = %+v, want only tt1", unfinished)
+	}
+
+	expectStatus(t, serve(t, router, http.MethodDelete, "/me/history/tt1", nil), http.StatusNoContent)
+	expectStatus(t, serve(t, router, http.MethodDelete, "/me/history/tt1", nil), http.StatusNotFound)
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/jobs"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// GetJob handles GET /jobs/:id requests
+// It reports the status of a review ranking job (pending, running, succeeded or failed).
+func GetJob(rankingJobs repository.RankingJobRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id, err := bson.ObjectIDFromHex(c.Param("id"))
+
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		job, err := rankingJobs.Get(ctx, id)
+
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch job"})
+			return
+		}
+
+		c.JSON(http.StatusOK, job)
+	}
+}
+
+// StartRerank handles POST /rankings/rerank requests
+// It starts re-classifying every movie's admin review against the current
+// rankings in the background. With dry_run the changes are only reported.
+func StartRerank(reranker *jobs.Reranker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		if reranker == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Re-ranking is not available"})
+			return
+		}
+
+		var req models.RerankRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		if err := validate.Struct(req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrorMessages(err)})
+			return
+		}
+
+		run, err := reranker.Start(req.DryRun, req.Concurrency)
+
+		if err != nil {
+			if errors.Is(err, jobs.ErrRerankRunning) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start re-rank"})
+			return
+		}
+
+		c.JSON(http.StatusAccepted, run)
+	}
+}
+
+// GetRerankRun handles GET /rankings/rerank/:id?page=&limit= requests
+// It reports the progress of a bulk re-rank and a page of the rankings it
+// changed and of the movies it failed to rank, in the order they were found.
+func GetRerankRun(runs repository.RerankRunRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		page, limit, ok := pageParams(c)
+
+		if !ok {
+			return
+		}
+
+		id, err := bson.ObjectIDFromHex(c.Param("id"))
+
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Re-rank run not found"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		run, err := runs.Get(ctx, id)
+
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Re-rank run not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch re-rank run"})
+			return
+		}
+
+		skip := (page - 1) * limit
+
+		if run.Diffs, err = runs.Diffs(ctx, id, skip, limit); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch re-rank run"})
+			return
+		}
+
+		if run.Failures, err = runs.Failures(ctx, id, skip, limit); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch re-rank run"})
+			return
+		}
+
+		c.JSON(http.StatusOK, run)
+	}
+}
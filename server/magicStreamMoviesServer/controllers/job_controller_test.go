@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/jobs"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+func TestGetRerankRun(t *testing.T) {
+	repos := newTestRepos()
+	ctx := context.Background()
+
+	run := jobs.NewRerankRun(true, 1)
+
+	if err := repos.RerankRuns.Insert(ctx, run); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, imdbId := range []string{"tt1", "tt2", "tt3"} {
+		if err := repos.RerankRuns.AddDiff(ctx, models.RankingDiff{RunID: run.ID, ImdbID: imdbId}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := repos.RerankRuns.AddFailure(ctx, models.RerankFailure{RunID: run.ID, ImdbID: "tt4", Error: "timeout"}); err != nil {
+		t.Fatal(err)
+	}
+
+	router := newTestRouter()
+	router.GET("/rankings/rerank/:id", GetRerankRun(repos.RerankRuns))
+
+	w := serve(t, router, http.MethodGet, "/rankings/rerank/"+run.ID.Hex()+"?page=2&limit=2", nil)
+	expectStatus(t, w, http.StatusOK)
+
+	var got models.RerankRun
+	decode(t, w, &got)
+
+	if len(got.Diffs) != 1 || got.Diffs[0].ImdbID != "tt3" || len(got.Failures) != 0 {
+		t.Fatalf("page 2 = %+v, want only the diff of tt3", got)
+	}
+
+	expectStatus(t, serve(t, router, http.MethodGet, "/rankings/rerank/nope", nil), http.StatusNotFound)
+	expectStatus(t, serve(t, router, http.MethodGet, "/rankings/rerank/"+jobs.NewRerankRun(true, 1).ID.Hex(), nil), http.StatusNotFound)
+}
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/recommend"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetList handles GET /me/watchlist and GET /me/favourites requests
+// It returns the user's list with the movie details joined in; the watchlist
+// is in the user's order, favourites newest first.
+func GetList(lists repository.ListRepository, list string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := utils.GetUserIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found in this context"})
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		entries, err := lists.Entries(ctx, userId, list)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch " + list})
+			return
+		}
+
+		c.JSON(http.StatusOK, entries)
+	}
+}
+
+// AddToList handles POST /me/watchlist and POST /me/favourites requests
+// New watchlist items go to the end of the list.
+func AddToList(movies repository.MovieRepository, lists repository.ListRepository, recommender *recommend.Engine, list string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := utils.GetUserIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found in this context"})
+			return
+		}
+
+		var input models.ListItemInput
+
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+
+		if err := validate.Struct(input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrorMessages(err)})
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		exists, err := movieExists(ctx, movies, input.ImdbID)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch movie"})
+			return
+		}
+
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
+			return
+		}
+
+		item := models.ListItem{
+			UserID:  userId,
+			List:    list,
+			ImdbID:  input.ImdbID,
+			AddedAt: time.Now(),
+		}
+
+		if err := lists.Add(ctx, &item); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Movie is already in your " + list})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add movie to " + list})
+			return
+		}
+
+		markFeedbackChanged(recommender, userId)
+
+		c.JSON(http.StatusCreated, item)
+	}
+}
+
+// RemoveFromList handles DELETE /me/watchlist/:imdb_id and DELETE /me/favourites/:imdb_id requests
+func RemoveFromList(lists repository.ListRepository, recommender *recommend.Engine, list string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := utils.GetUserIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found in this context"})
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		if err := lists.Remove(ctx, userId, list, c.Param("imdb_id")); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Movie is not in your " + list})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove movie from " + list})
+			return
+		}
+
+		markFeedbackChanged(recommender, userId)
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// ReorderWatchlist handles PUT /me/watchlist/order requests
+// The body lists every imdb_id of the watchlist exactly once, in the new order.
+func ReorderWatchlist(lists repository.ListRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := utils.GetUserIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found in this context"})
+			return
+		}
+
+		var req models.ListOrder
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+
+		if err := validate.Struct(req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrorMessages(err)})
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		items, err := lists.Items(ctx, userId, models.ListWatchlist)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch watchlist"})
+			return
+		}
+
+		saved := map[string]bool{}
+
+		for _, item := range items {
+			saved[item.ImdbID] = true
+		}
+
+		seen := map[string]bool{}
+
+		for _, imdbId := range req.Order {
+			if !saved[imdbId] || seen[imdbId] {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "order must list every watchlist movie exactly once: " + imdbId})
+				return
+			}
+			seen[imdbId] = true
+		}
+
+		if len(seen) != len(saved) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "order must list every movie in the watchlist"})
+			return
+		}
+
+		if err := lists.Reorder(ctx, userId, models.ListWatchlist, req.Order); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder watchlist"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
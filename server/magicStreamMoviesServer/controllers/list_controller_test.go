@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+func TestWatchlist(t *testing.T) {
+	repos := newTestRepos()
+	addTestMovie(t, repos.Movies, "tt1", "Heat")
+	addTestMovie(t, repos.Movies, "tt2", "Ronin")
+	addTestMovie(t, repos.Movies, "tt3", "Collateral")
+
+	router := newTestRouter()
+	router.GET("/me/watchlist", GetList(repos.Lists, models.ListWatchlist))
+	router.POST("/me/watchlist", AddToList(repos.Movies, repos.Lists, nil, models.ListWatchlist))
+	router.PUT("/me/watchlist/order", ReorderWatchlist(repos.Lists))
+	router.DELETE("/me/watchlist/:imdb_id", RemoveFromList(repos.Lists, nil, models.ListWatchlist))
+
+	for _, imdbId := range []string{"tt1", "tt2", "tt3"} {
+		w := serve(t, router, http.MethodPost, "/me/watchlist", models.ListItemInput{ImdbID: imdbId})
+		expectStatus(t, w, http.StatusCreated)
+	}
+
+	expectStatus(t, serve(t, router, http.MethodPost, "/me/watchlist", models.ListItemInput{ImdbID: "tt1"}), http.StatusConflict)
+	expectStatus(t, serve(t, router, http.MethodPost, "/me/watchlist", models.ListItemInput{ImdbID: "tt9"}), http.StatusNotFound)
+
+	expectOrder := func(want ...string) {
+		t.Helper()
+
+		w := serve(t, router, http.MethodGet, "/me/watchlist", nil)
+		expectStatus(t, w, http.StatusOK)
+
+		var entries []models.ListEntry
+		decode(t, w, &entries)
+
+		if len(entries) != len(want) {
+			t.Fatalf("watchlist has %d entries, want %v", len(entries), want)
+		}
+
+		for i, entry := range entries {
+			if entry.ImdbID != want[i] || entry.Movie.ImdbID != want[i] {
+				t.Fatalf("watchlist entry %d = %s, want %v", i, entry.ImdbID, want)
+			}
+		}
+	}
+
+	expectOrder("tt1", "tt2", "tt3")
+
+	w := serve(t, router, http.MethodPut, "/me/watchlist/order", models.ListOrder{Order: []string{"tt3", "tt1", "tt2"}})
+	expectStatus(t, w, http.StatusNoContent)
+	expectOrder("tt3", "tt1", "tt2")
+
+	// the order must name every saved movie exactly once
+	w = serve(t, router, http.MethodPut, "/me/watchlist/order", models.ListOrder{Order: []string{"tt3", "tt1"}})
+	expectStatus(t, w, http.StatusBadRequest)
+
+	w = serve(t, router, http.MethodPut, "/me/watchlist/order", models.ListOrder{Order: []string{"tt3", "tt3", "tt1"}})
+	expectStatus(t, w, http.StatusBadRequest)
+
+	expectStatus(t, serve(t, router, http.MethodDelete, "/me/watchlist/tt1", nil), http.StatusNoContent)
+	expectStatus(t, serve(t, router, http.MethodDelete, "/me/watchlist/tt1", nil), http.StatusNotFound)
+	expectOrder("tt3", "tt2")
+}
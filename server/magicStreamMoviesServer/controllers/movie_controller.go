@@ -2,62 +2,209 @@ package controllers
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
-	"fmt"
+	"log"
 	"net/http"
-	"os"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/database"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/embedder"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/jobs"
 	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/ranker"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/recommend"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
 	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"github.com/tmc/langchaingo/llms/openai"
 	"go.mongodb.org/mongo-driver/v2/bson"
-	"go.mongodb.org/mongo-driver/v2/mongo"
-	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
-var movieCollection *mongo.Collection = database.OpenCollection("movies")
-var rankingCollection *mongo.Collection = database.OpenCollection("rankings")
 var validate = validator.New()
 
+// movieSortFields lists the values accepted by the sort query parameter
+var movieSortFields = map[string]string{
+	"title":   repository.SortByTitle,
+	"ranking": repository.SortByRanking,
+}
+
+// movieCursor is the opaque keyset position handed out as next_cursor.
+// It records the sort key and _id of the last movie on the previous page.
+type movieCursor struct {
+	Sort    string `json:"s"`
+	Title   string `json:"t,omitempty"`
+	Ranking int    `json:"r,omitempty"`
+	ID      string `json:"id"`
+}
+
+func encodeMovieCursor(sort string, movie models.Movie) string {
+	cur := movieCursor{Sort: sort, Title: movie.Title, Ranking: movie.Ranking.RankingValue, ID: movie.ID.Hex()}
+	raw, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeMovieCursor(token string) (movieCursor, error) {
+	var cur movieCursor
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+
+	if err != nil {
+		return cur, err
+	}
+
+	err = json.Unmarshal(raw, &cur)
+
+	return cur, err
+}
+
+// parseMovieFilters translates the GET /movies query string into a MovieQuery.
+// Supported filters: genre and genre_id (comma separated), ranking_name,
+// min_ranking/max_ranking (ranking_value range) and title (case-insensitive prefix).
+func parseMovieFilters(c *gin.Context) (repository.MovieQuery, error) {
+	var query repository.MovieQuery
+
+	if genres := c.Query("genre"); genres != "" {
+		for _, name := range strings.Split(genres, ",") {
+			query.GenreNames = append(query.GenreNames, strings.TrimSpace(name))
+		}
+	}
+
+	if genreIds := c.Query("genre_id"); genreIds != "" {
+		for _, raw := range strings.Split(genreIds, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(raw))
+			if err != nil {
+				return query, errors.New("genre_id must be a comma separated list of integers")
+			}
+			query.GenreIDs = append(query.GenreIDs, id)
+		}
+	}
+
+	query.RankingName = c.Query("ranking_name")
+
+	if minRanking := c.Query("min_ranking"); minRanking != "" {
+		val, err := strconv.Atoi(minRanking)
+		if err != nil {
+			return query, errors.New("min_ranking must be an integer")
+		}
+		query.MinRanking = &val
+	}
+
+	if maxRanking := c.Query("max_ranking"); maxRanking != "" {
+		val, err := strconv.Atoi(maxRanking)
+		if err != nil {
+			return query, errors.New("max_ranking must be an integer")
+		}
+		query.MaxRanking = &val
+	}
+
+	query.TitlePrefix = c.Query("title")
+
+	return query, nil
+}
+
 // GetMovies handles GET /movies requests
-// It retrieves all movies from the MongoDb collection and returns them as JSON.
-func GetMovies() gin.HandlerFunc {
+// It returns one page of movies matching the query filters, sorted by title or
+// ranking. Pages are selected either with page/limit or with the next_cursor
+// returned by a previous call, and the response carries the total match count.
+func GetMovies(movies repository.MovieRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
+		limit, err := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+
+		if err != nil || limit < 1 || limit > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+
+		page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+
+		if err != nil || page < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "page must be a positive integer"})
+			return
+		}
+
+		query, err := parseMovieFilters(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// sort=title|ranking, prefix with "-" for descending order
+		sortParam := c.DefaultQuery("sort", "title")
+		query.Descending = strings.HasPrefix(sortParam, "-")
+
+		sortField, ok := movieSortFields[strings.TrimPrefix(sortParam, "-")]
+
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be one of title, -title, ranking, -ranking"})
+			return
+		}
+
+		query.SortField = sortField
+
 		// Create a context with timeout to aoivd long-running database operatiosn
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
 
 		defer cancel()
 
-		var movies []models.Movie
+		total, err := movies.Count(ctx, query)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count movies."})
+			return
+		}
+
+		// Fetch one extra movie to detect a next page
+		query.Limit = limit + 1
+
+		if cursorParam := c.Query("cursor"); cursorParam != "" {
+			cur, err := decodeMovieCursor(cursorParam)
+
+			if err != nil || cur.Sort != sortParam {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+				return
+			}
+
+			lastId, err := bson.ObjectIDFromHex(cur.ID)
+
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+				return
+			}
+
+			query.After = &repository.MovieCursor{Title: cur.Title, Ranking: cur.Ranking, ID: lastId}
+			page = 0
+		} else {
+			query.Skip = (page - 1) * limit
+		}
 
-		// Find all documents in the collection (empty filter)
-		cursor, err := movieCollection.Find(ctx, bson.M{})
+		pageMovies, err := movies.List(ctx, query)
 
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch movies."})
+			return
 		}
 
-		defer cursor.Close(ctx)
+		result := models.MoviePage{Total: total, Page: page, Limit: limit}
 
-		// Decode all documents into the movies slice
-		if err = cursor.All(ctx, &movies); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode movies."})
+		if int64(len(pageMovies)) > limit {
+			pageMovies = pageMovies[:limit]
+			result.NextCursor = encodeMovieCursor(sortParam, pageMovies[len(pageMovies)-1])
 		}
 
-		// Return the movies as a JSON response
-		c.JSON(http.StatusOK, movies)
+		result.Movies = pageMovies
+
+		// Return the page as a JSON response
+		c.JSON(http.StatusOK, result)
 
 	}
 }
 
-func GetMovie() gin.HandlerFunc {
+func GetMovie(movies repository.MovieRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
 
@@ -70,9 +217,7 @@ func GetMovie() gin.HandlerFunc {
 			return
 		}
 
-		var movie models.Movie
-
-		err := movieCollection.FindOne(ctx, bson.M{"imdb_id": movieID}).Decode(&movie)
+		movie, err := movies.Get(ctx, movieID)
 
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
@@ -84,7 +229,7 @@ func GetMovie() gin.HandlerFunc {
 	}
 }
 
-func AddMovie() gin.HandlerFunc {
+func AddMovie(movies repository.MovieRepository, rankings repository.RankingRepository, genreRepo repository.GenreRepository, similarIndex *embedder.Index) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
@@ -110,280 +255,458 @@ func AddMovie() gin.HandlerFunc {
 			return
 		}
 
-		result, err := movieCollection.InsertOne(ctx, movie)
+		if !checkMovieRanking(c, ctx, rankings, movie.Ranking) {
+			return
+		}
 
-		if err != nil {
+		genres, ok := checkGenres(c, ctx, genreRepo, movie.Genre)
+
+		if !ok {
+			return
+		}
+
+		movie.Genre = genres
+
+		// server maintained fields start empty
+		movie.RankingStatus = ""
+		movie.RankingJobID = ""
+		movie.AverageRating = 0
+		movie.RatingCount = 0
+
+		if err := movies.Insert(ctx, &movie); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.JSON(http.StatusConflict, gin.H{"error": "A movie with this imdb_id already exists"})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add movie"})
 			return
 		}
 
-		c.JSON(http.StatusCreated, result)
+		markMovieTextChanged(similarIndex, movie.ImdbID)
+
+		c.JSON(http.StatusCreated, gin.H{"InsertedID": movie.ID})
 
 	}
 }
 
-// AdminReviewUpdate handles updating an admin review and generating its ranking using AI
-func AdminReviewUpdate() gin.HandlerFunc {
-	return func(c *gin.Context) {
+// pageParams parses the page and limit query parameters, responding with 400 when they are invalid
+func pageParams(c *gin.Context) (int64, int64, bool) {
+	page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
 
-		role, err := utils.GetRoleFromContext(c)
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page must be a positive integer"})
+		return 0, 0, false
+	}
 
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Role not found in context"})
-			return
-		}
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
 
-		if role != "ADMIN" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User must be a ADMIN role"})
-			return
-		}
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return 0, 0, false
+	}
+
+	return page, limit, true
+}
+
+// validationErrorMessages turns validator errors into "Field failed on tag" messages
+func validationErrorMessages(err error) []string {
+	var messages []string
+
+	var validationErrors validator.ValidationErrors
+
+	if !errors.As(err, &validationErrors) {
+		return []string{err.Error()}
+	}
+
+	for _, fieldErr := range validationErrors {
+		messages = append(messages, fieldErr.Field()+" failed on "+fieldErr.Tag())
+	}
+
+	return messages
+}
+
+// checkMovieRanking responds with 400 unless ranking is part of the rankings collection
+func checkMovieRanking(c *gin.Context, ctx context.Context, rankings repository.RankingRepository, ranking models.Ranking) bool {
+	exists, err := rankings.Exists(ctx, ranking)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check ranking"})
+		return false
+	}
+
+	if !exists {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ranking must match an existing ranking_value and ranking_name"})
+		return false
+	}
+
+	return true
+}
+
+// reviewFieldsError rejects movie updates that try to change the admin review
+// or ranking, which must go through AdminReviewUpdate so the ranking is queued
+const reviewFieldsError = "admin_review and ranking cannot be changed here; use PATCH /updatereview/:imdb_id"
+
+// UpdateMovie handles PUT /movie/:imdb_id requests
+// It replaces every editable field of an existing movie with the validated
+// request body; fields maintained by the server (rating aggregates, ranking
+// job status) are kept. The body must repeat the stored admin_review and
+// ranking, which only change through PATCH /updatereview/:imdb_id.
+func UpdateMovie(movies repository.MovieRepository, genreRepo repository.GenreRepository, references []repository.MovieReferenceRepository, similarIndex *embedder.Index) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
 
 		movieId := c.Param("imdb_id")
 
-		if movieId == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Movie Id is required"})
+		var movie models.Movie
+
+		if err := c.ShouldBindJSON(&movie); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
 			return
 		}
 
-		// Define request body structure to bind incoming JSON.
-		var req struct {
-			AdminReview string `json:"admin_review"`
+		// The path identifies the movie; the body may omit imdb_id to keep it
+		if movie.ImdbID == "" {
+			movie.ImdbID = movieId
 		}
 
-		// Define request body structure to bind incoming JSON.
-		var resp struct {
-			RankingName string `json:"rankin_name"`
-			AdminReview string `json:"admin_review"`
+		if err := validate.Struct(movie); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrorMessages(err)})
+			return
 		}
 
-		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		stored, err := movies.Get(ctx, movieId)
+
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch movie"})
 			return
 		}
 
-		// Define response structure to return ranking and review.
-		sentiment, rankVal, err := GetReviewRanking(req.AdminReview)
+		if movie.AdminReview != stored.AdminReview || movie.Ranking != stored.Ranking {
+			c.JSON(http.StatusBadRequest, gin.H{"error": reviewFieldsError})
+			return
+		}
 
-		// if err != nil {
-		// 	c.JSON(http.StatusInternalServerError, gin.H{"error": "ERROR getting review ranking"})
-		// 	return
-		// }
+		genres, ok := checkGenres(c, ctx, genreRepo, movie.Genre)
 
-		if err != nil {
-			fmt.Println("ERROR:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if !ok {
 			return
 		}
 
-		// Create MongoDB filter to find the movie by imdb_id.
-		filter := bson.M{"imdb_id": movieId}
+		movie.Genre = genres
 
-		// Create context with timeout to prevent long-running DB operations.
-		update := bson.M{
-			"$set": bson.M{
-				"admin_review": req.AdminReview,
-				"ranking": bson.M{
-					"ranking_value": rankVal,
-					"ranking_name":  sentiment,
-				},
-			},
+		patch := models.MoviePatch{
+			ImdbID:     &movie.ImdbID,
+			Title:      &movie.Title,
+			PosterPath: &movie.PosterPath,
+			YouTubeID:  &movie.YouTubeID,
+			Genre:      &movie.Genre,
 		}
 
-		// Create context with timeout to prevent long-running DB operations.
-		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
-		defer cancel()
-
-		// Execute MongoDB update operation.
-		result, err := movieCollection.UpdateOne(ctx, filter, update)
+		updated, err := movies.Update(ctx, movieId, patch)
 
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating movie"})
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
+				return
+			}
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.JSON(http.StatusConflict, gin.H{"error": "A movie with this imdb_id already exists"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update movie"})
 			return
 		}
 
-		if result.MatchedCount == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
-			return
+		if updated.ImdbID != movieId {
+			if err := moveMovieReferences(ctx, references, movieId, updated.ImdbID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Movie updated but its user data could not be moved"})
+				return
+			}
 		}
 
-		resp.RankingName = sentiment
-		resp.AdminReview = req.AdminReview
-
-		c.JSON(http.StatusOK, resp)
+		markMovieTextChanged(similarIndex, movieId, updated.ImdbID)
 
+		c.JSON(http.StatusOK, updated)
 	}
 }
 
-// GetReviewRanking determines the ranking category and numeric value using AI.
-func GetReviewRanking(admin_review string) (string, int, error) {
+// PatchMovie handles PATCH /movie/:imdb_id requests
+// Only the fields present in the request body are validated and updated.
+// admin_review and ranking are rejected; they change through PATCH /updatereview/:imdb_id.
+func PatchMovie(movies repository.MovieRepository, genreRepo repository.GenreRepository, references []repository.MovieReferenceRepository, similarIndex *embedder.Index) gin.HandlerFunc {
+	return func(c *gin.Context) {
 
-	// Retrieve all ranking definitions from database.
-	rankings, err := GetRankings()
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
 
-	if err != nil {
-		return "", 0, err
-	}
+		movieId := c.Param("imdb_id")
 
-	sentimentDelimited := ""
+		var req models.MoviePatchRequest
 
-	for _, ranking := range rankings {
-		// Exclude special ranking value (e.g., 999 used as placeholder).
-		if ranking.RankingValue != 999 {
-			sentimentDelimited = sentimentDelimited + ranking.RankingName + ","
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
 		}
-	}
 
-	// remove trailing comma
-	sentimentDelimited = strings.Trim(sentimentDelimited, ",")
+		if req.AdminReview != nil || req.Ranking != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": reviewFieldsError})
+			return
+		}
 
-	// Load env variables and read OpenAI API key
-	// err = godotenv.Load(".env")
+		patch := req.MoviePatch
 
-	// if err != nil {
-	// 	log.Println("Warning: .env file not found")
-	// }
+		if err := validate.Struct(patch); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrorMessages(err)})
+			return
+		}
 
-	OpenAIApiKey := os.Getenv("OPENAI_API_KEY")
+		if patch == (models.MoviePatch{}) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+			return
+		}
 
-	if OpenAIApiKey == "" {
-		return "", 0, errors.New("could not read OPENAI_API_KEY")
-	}
+		if patch.Genre != nil {
+			genres, ok := checkGenres(c, ctx, genreRepo, *patch.Genre)
+			if !ok {
+				return
+			}
+			patch.Genre = &genres
+		}
 
-	// Initialize OpenAI client
-	llm, err := openai.New(openai.WithToken(OpenAIApiKey))
+		updated, err := movies.Update(ctx, movieId, patch)
 
-	if err != nil {
-		return "", 0, err
-	}
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
+				return
+			}
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.JSON(http.StatusConflict, gin.H{"error": "A movie with this imdb_id already exists"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update movie"})
+			return
+		}
 
-	// Retrieve base promtp template form env
-	base_prompt_template := os.Getenv("BASE_PROMPT_TEMPLATE")
+		if updated.ImdbID != movieId {
+			if err := moveMovieReferences(ctx, references, movieId, updated.ImdbID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Movie updated but its user data could not be moved"})
+				return
+			}
+		}
 
-	// Replace placeholder with actual ranking list
-	base_prompt := strings.Replace(base_prompt_template, "{rankings}", sentimentDelimited, 1)
+		markMovieTextChanged(similarIndex, movieId, updated.ImdbID)
 
-	// Call OpenAI model with constructed prompt
-	response, err := llm.Call(context.Background(), base_prompt+admin_review)
+		c.JSON(http.StatusOK, updated)
+	}
+}
 
-	if err != nil {
-		return "", 0, err
+// moveMovieReferences re-points the per-user data and the recommendation
+// data after a movie's imdb_id changed
+func moveMovieReferences(ctx context.Context, references []repository.MovieReferenceRepository, fromImdbId, toImdbId string) error {
+	for _, reference := range references {
+		if err := reference.MoveMovie(ctx, fromImdbId, toImdbId); err != nil {
+			return err
+		}
 	}
 
-	// Initialize ranking value
-	rankVal := 0
+	return nil
+}
 
-	// Map AI response to its numeric ranking value
-	for _, ranking := range rankings {
-		if ranking.RankingName == response {
-			rankVal = ranking.RankingValue
-			break
+// deleteMovieReferences removes the per-user data and the recommendation
+// data of a deleted movie
+func deleteMovieReferences(ctx context.Context, references []repository.MovieReferenceRepository, imdbId string) error {
+	for _, reference := range references {
+		if err := reference.DeleteMovie(ctx, imdbId); err != nil {
+			return err
 		}
 	}
 
-	// Return ranking name and numeric value
-	return response, rankVal, nil
-
+	return nil
 }
 
-// GetRankings retrieves all ranking records from MongoDB
-func GetRankings() ([]models.Ranking, error) {
-	var rankings []models.Ranking
+// DeleteMovie handles DELETE /movie/:imdb_id requests
+func DeleteMovie(movies repository.MovieRepository, references []repository.MovieReferenceRepository, similarIndex *embedder.Index) gin.HandlerFunc {
+	return func(c *gin.Context) {
 
-	var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
-	defer cancel()
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
 
-	// Query MongoDB collection without filters
-	cursor, err := rankingCollection.Find(ctx, bson.M{})
+		if err := movies.Delete(ctx, c.Param("imdb_id")); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete movie"})
+			return
+		}
 
-	if err != nil {
-		return nil, err
-	}
+		if err := deleteMovieReferences(ctx, references, c.Param("imdb_id")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Movie deleted but its user data could not be removed"})
+			return
+		}
 
-	defer cursor.Close(ctx)
+		markMovieTextChanged(similarIndex, c.Param("imdb_id"))
 
-	// decode all documents into rankings slice
-	if err := cursor.All(ctx, &rankings); err != nil {
-		return nil, err
+		c.Status(http.StatusNoContent)
 	}
-
-	return rankings, nil
 }
 
-// GetRecommendedMovies return a Gin handler that provides movie recommendations
-// based on the authenticated user's favourite genres.
-func GetRecommendedMovies() gin.HandlerFunc {
+// AdminReviewUpdate handles updating an admin review and queues its AI ranking.
+// The review is saved immediately with a pending ranking_status; the returned
+// job id can be polled on GET /jobs/:id until the ranking is available. If the
+// job cannot be queued the ranking_status is set to failed.
+func AdminReviewUpdate(movies repository.MovieRepository, rankingQueue *jobs.RankingQueue, similarIndex *embedder.Index) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
-		// retrieve the authenticated userId from the Gin context
-		userId, err := utils.GetUserIdFromContext(c)
+		movieId := c.Param("imdb_id")
 
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found int this context"})
+		if movieId == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Movie Id is required"})
 			return
 		}
 
-		// Fetch the user's favourite genres from the database
-		favourite_genres, err := GetUsersFavouriteGenres(userId)
+		if rankingQueue == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Review ranking is not available"})
+			return
+		}
 
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		// Define request body structure to bind incoming JSON.
+		var req struct {
+			AdminReview string `json:"admin_review"`
+		}
+
+		// Define response structure to return the review and its ranking job.
+		var resp struct {
+			AdminReview   string `json:"admin_review"`
+			RankingStatus string `json:"ranking_status"`
+			JobID         string `json:"job_id"`
+		}
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 			return
 		}
 
-		// Load env variables from .env file
-		//err = godotenv.Load(".env")
+		jobId := bson.NewObjectID()
 
-		// if err != nil {
-		// 	log.Println("Warning: .env file not found")
-		// }
+		// Create context with timeout to prevent long-running DB operations.
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
 
-		// Set a default limit for recommended movies
-		var recommendedMovieLimitVal int64 = 5
+		// Save the review now; the ranking is filled in by the background job.
+		if err := movies.SetAdminReview(ctx, movieId, req.AdminReview, jobId.Hex()); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating movie"})
+			return
+		}
 
-		// retrieve the limit value from env variables
-		// recommendedMovieLimitStr := os.Getenv("RECOMMENDED_MOVIE_LIMIT")
+		if _, err := rankingQueue.Enqueue(ctx, jobId, movieId, req.AdminReview); err != nil {
+			log.Println("Warning: unable to queue ranking for", movieId, err)
 
-		// if recommendedMovieLimitStr != "" {
-		// 	recommendedMovieLimitVal, _ = strconv.ParseInt(recommendedMovieLimitStr, 10, 64)
-		// }
-		if val := os.Getenv("RECOMMENDED_MOVIE_LIMIT"); val != "" {
-			parsed, err := strconv.ParseInt(val, 10, 64)
-			if err == nil {
-				recommendedMovieLimitVal = parsed
+			// No job exists to move the movie out of pending, so record the
+			// ranking as failed; sending the review again retries it.
+			if err := movies.SetRankingResult(ctx, movieId, jobId.Hex(), models.JobStatusFailed, nil); err != nil {
+				log.Println("Warning: unable to mark ranking failed for", movieId, err)
 			}
+
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Review saved but ranking could not be queued; send it again to retry"})
+			return
+		}
+
+		resp.AdminReview = req.AdminReview
+		resp.RankingStatus = models.JobStatusPending
+		resp.JobID = jobId.Hex()
+
+		markMovieTextChanged(similarIndex, movieId)
+
+		c.JSON(http.StatusAccepted, resp)
+
+	}
+}
+
+// markFeedbackChanged schedules the user's recommendations for recomputation
+func markFeedbackChanged(recommender *recommend.Engine, userId string) {
+	if recommender != nil {
+		recommender.MarkDirty(userId)
+	}
+}
+
+// GetReviewRanking determines the ranking category and numeric value using reviewRanker.
+func GetReviewRanking(ctx context.Context, reviewRanker ranker.ReviewRanker, rankings repository.RankingRepository, admin_review string) (string, int, error) {
+
+	if reviewRanker == nil {
+		return "", 0, errors.New("review ranker is not configured")
+	}
+
+	// Retrieve all ranking definitions from database.
+	scale, err := rankings.List(ctx)
+
+	if err != nil {
+		return "", 0, err
+	}
+
+	var candidates []models.Ranking
+
+	for _, ranking := range scale {
+		// Exclude rankings that are not meant to be chosen by the AI (e.g. placeholders).
+		if !ranking.ExcludedFromAI {
+			candidates = append(candidates, ranking)
 		}
+	}
+
+	ranking, err := reviewRanker.RankReview(ctx, admin_review, candidates)
 
-		// Create MongoDB find options
-		findOptions := options.Find()
+	if err != nil {
+		return "", 0, err
+	}
+
+	// Return ranking name and numeric value
+	return ranking.RankingName, ranking.RankingValue, nil
 
-		// Sort results by ranking value in ascending order
-		findOptions.SetSort(bson.D{{Key: "ranking.ranking_value", Value: 1}})
+}
+
+// GetRecommendedMovies return a Gin handler that provides movie recommendations
+// based on the authenticated user's ratings, lists, watch history and favourite
+// genres. It returns up to limit movies, each with a score breakdown and the
+// reason it was chosen.
+func GetRecommendedMovies(repos *repository.Repositories, limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
 
-		// Limit the number of returned documents
-		findOptions.SetLimit(recommendedMovieLimitVal)
+		// retrieve the authenticated userId from the Gin context
+		userId, err := utils.GetUserIdFromContext(c)
 
-		// Filter movies whose genre name is within the user's faavourite genres
-		filter := bson.M{"genre.genre_name": bson.M{"$in": favourite_genres}}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found int this context"})
+			return
+		}
 
 		// Create a context with timeout to avoid long running queries
 		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
 		defer cancel()
 
-		// Execute the mongoDB query
-		cursor, err := movieCollection.Find(ctx, filter, findOptions)
+		// Fetch the user's favourite genres from the database
+		favourite_genres, err := GetUsersFavouriteGenres(ctx, repos.Users, userId)
 
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching recommended movies"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		defer cursor.Close(ctx)
+		// Blend collaborative filtering scores with the favourite genres and explain each pick
+		recommendedMovies, err := recommend.Recommend(ctx, repos, userId, favourite_genres, limit)
 
-		var recommendedMovies []models.Movie
-
-		// Decode all documents from the cursor into the slice
-		if err := cursor.All(ctx, &recommendedMovies); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching recommended movies"})
 			return
 		}
 
@@ -393,61 +716,20 @@ func GetRecommendedMovies() gin.HandlerFunc {
 }
 
 // retrieves a list of favourite genre name for a given user ID
-func GetUsersFavouriteGenres(userId string) ([]string, error) {
-	var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
-	defer cancel()
-
-	// define a filter to find the user by user_id
-	filter := bson.M{"user_id": userId}
-
-	// define ea projection to only return favourite_genre.genre_nmae field
-	projection := bson.M{
-		"favourite_genres.genre_name": 1,
-		"_id":                         0,
-	}
-
-	// Apply projection to the FindONe optiosn
-	opts := options.FindOne().SetProjection(projection)
-
-	// Create a generic BSON map to store the result
-	var result bson.M
-
-	// Execute the query and decode the result
-	err := userCollection.FindOne(ctx, filter, opts).Decode(&result)
+func GetUsersFavouriteGenres(ctx context.Context, users repository.UserRepository, userId string) ([]string, error) {
+	user, err := users.Get(ctx, userId)
 
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if errors.Is(err, repository.ErrNotFound) {
 			return []string{}, nil
 		}
+		return nil, errors.New("unable to retrieve favourite genres for user")
 	}
 
-	// Extract favourite_genres as a BSON array
-	favGenresArray, ok := result["favourite_genres"].(bson.A)
-
-	if !ok {
-		return []string{}, errors.New("unable to retrieve favourite genres for user")
-	}
-
-	var genreNames []string
-
-	// iterate over each genre item in the array
-	for _, item := range favGenresArray {
+	genreNames := make([]string, 0, len(user.FavouriteGenres))
 
-		// Convert each item into BSON document
-		if genreMap, ok := item.(bson.D); ok {
-
-			//Iterate over fiedls inside the document
-			for _, elem := range genreMap {
-
-				// check if the field is "genre_name"
-				if elem.Key == "genre_name" {
-					// Assert the value as string and append it
-					if name, ok := elem.Value.(string); ok {
-						genreNames = append(genreNames, name)
-					}
-				}
-			}
-		}
+	for _, genre := range user.FavouriteGenres {
+		genreNames = append(genreNames, genre.GenreName)
 	}
 
 	// return the extracted genre names
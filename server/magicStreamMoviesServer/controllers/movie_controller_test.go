@@ -0,0 +1,231 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/jobs"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// addTestUserData gives testUserId a review, a watchlist item and a history
+// entry for the movie, plus stored interactions and similarities
+func addTestUserData(t *testing.T, repos *repository.Repositories, imdbId string) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	if err := repos.Reviews.Insert(ctx, &models.UserReview{UserID: testUserId, ImdbID: imdbId, Rating: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repos.Lists.Add(ctx, &models.ListItem{UserID: testUserId, List: models.ListWatchlist, ImdbID: imdbId}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repos.History.Record(ctx, models.WatchProgress{UserID: testUserId, ImdbID: imdbId, Progress: 1, Completed: true, UpdatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repos.Interactions.Replace(ctx, testUserId, map[string]float64{imdbId: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repos.Similarities.Save(ctx, imdbId, []models.Neighbour{{ImdbID: "tt2", Score: 0.5}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// countUserData returns how many of the review, list, history, interaction
+// and similarity entries reference the movie
+func countUserData(t *testing.T, repos *repository.Repositories, imdbId string) int {
+	t.Helper()
+
+	ctx := context.Background()
+	count := 0
+
+	reviews, err := repos.Reviews.CountByMovie(ctx, imdbId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count += int(reviews)
+
+	items, err := repos.Lists.Items(ctx, testUserId, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range items {
+		if item.ImdbID == imdbId {
+			count++
+		}
+	}
+
+	history, err := repos.History.ListByUser(ctx, testUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, watched := range history {
+		if watched.ImdbID == imdbId {
+			count++
+		}
+	}
+
+	interactions, err := repos.Interactions.ListByMovie(ctx, imdbId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count += len(interactions)
+
+	similarities, err := repos.Similarities.ListByMovies(ctx, []string{imdbId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count += len(similarities)
+
+	return count
+}
+
+func TestDeleteMovieRemovesUserData(t *testing.T) {
+	repos := newTestRepos()
+	addTestMovie(t, repos.Movies, "tt1", "Heat")
+	addTestUserData(t, repos, "tt1")
+
+	router := newTestRouter()
+	router.DELETE("/movie/:imdb_id", DeleteMovie(repos.Movies, repos.MovieReferences(), nil))
+
+	expectStatus(t, serve(t, router, http.MethodDelete, "/movie/tt1", nil), http.StatusNoContent)
+
+	if n := countUserData(t, repos, "tt1"); n != 0 {
+		t.Fatalf("%d entries still reference the deleted movie", n)
+	}
+
+	expectStatus(t, serve(t, router, http.MethodDelete, "/movie/tt1", nil), http.StatusNotFound)
+}
+
+func TestPatchMovieImdbIdMovesUserData(t *testing.T) {
+	repos := newTestRepos()
+	addTestMovie(t, repos.Movies, "tt1", "Heat")
+	addTestUserData(t, repos, "tt1")
+
+	router := newTestRouter()
+	router.PATCH("/movie/:imdb_id", PatchMovie(repos.Movies, repos.Genres, repos.MovieReferences(), nil))
+
+	renamed := "tt0113277"
+
+	w := serve(t, router, http.MethodPatch, "/movie/tt1", models.MoviePatch{ImdbID: &renamed})
+	expectStatus(t, w, http.StatusOK)
+
+	if n := countUserData(t, repos, "tt1"); n != 0 {
+		t.Fatalf("%d entries still reference the old imdb_id", n)
+	}
+
+	if n := countUserData(t, repos, renamed); n != 5 {
+		t.Fatalf("%d entries reference the new imdb_id, want 5", n)
+	}
+}
+
+func TestPatchMovieRejectsUnknownGenre(t *testing.T) {
+	repos := newTestRepos()
+	addTestMovie(t, repos.Movies, "tt1", "Heat", testAction)
+
+	router := newTestRouter()
+	router.PATCH("/movie/:imdb_id", PatchMovie(repos.Movies, repos.Genres, repos.MovieReferences(), nil))
+
+	genres := []models.Genre{{GenreID: 7, GenreName: "Western"}}
+
+	w := serve(t, router, http.MethodPatch, "/movie/tt1", models.MoviePatch{Genre: &genres})
+	expectStatus(t, w, http.StatusBadRequest)
+
+	genres = []models.Genre{{GenreID: 2, GenreName: "drama"}}
+
+	w = serve(t, router, http.MethodPatch, "/movie/tt1", models.MoviePatch{Genre: &genres})
+	expectStatus(t, w, http.StatusOK)
+
+	var movie models.Movie
+	decode(t, w, &movie)
+
+	if len(movie.Genre) != 1 || movie.Genre[0] != testDrama {
+		t.Fatalf("genres = %+v, want the canonical Drama", movie.Genre)
+	}
+}
+
+func TestMovieUpdatesRejectReviewAndRanking(t *testing.T) {
+	repos := newTestRepos()
+
+	movie := models.Movie{
+		ImdbID:      "tt1",
+		Title:       "Heat",
+		PosterPath:  "https://example.com/heat.jpg",
+		YouTubeID:   "yt1",
+		Genre:       []models.Genre{testAction},
+		AdminReview: "Tense",
+		Ranking:     models.Ranking{RankingValue: 1, RankingName: "Excellent"},
+	}
+
+	if err := repos.Movies.Insert(context.Background(), &movie); err != nil {
+		t.Fatal(err)
+	}
+
+	router := newTestRouter()
+	router.PUT("/movie/:imdb_id", UpdateMovie(repos.Movies, repos.Genres, repos.MovieReferences(), nil))
+	router.PATCH("/movie/:imdb_id", PatchMovie(repos.Movies, repos.Genres, repos.MovieReferences(), nil))
+
+	expectStatus(t, serve(t, router, http.MethodPatch, "/movie/tt1", gin.H{"admin_review": "Dull"}), http.StatusBadRequest)
+	expectStatus(t, serve(t, router, http.MethodPatch, "/movie/tt1", gin.H{"ranking": gin.H{"ranking_value": 3, "ranking_name": "Bad"}}), http.StatusBadRequest)
+
+	changed := movie
+	changed.AdminReview = "Dull"
+	expectStatus(t, serve(t, router, http.MethodPut, "/movie/tt1", changed), http.StatusBadRequest)
+
+	// repeating the stored review and ranking is allowed
+	renamed := movie
+	renamed.Title = "Heat (1995)"
+	expectStatus(t, serve(t, router, http.MethodPut, "/movie/tt1", renamed), http.StatusOK)
+
+	stored, err := repos.Movies.Get(context.Background(), "tt1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.Title != "Heat (1995)" || stored.AdminReview != "Tense" || stored.Ranking != movie.Ranking {
+		t.Fatalf("movie = %+v, want only the title changed", stored)
+	}
+}
+
+// failingJobs is a job repository whose inserts always fail
+type failingJobs struct {
+	*repository.MemoryRankingJobRepository
+}
+
+func (failingJobs) Insert(ctx context.Context, job models.RankingJob) error {
+	return errors.New("insert failed")
+}
+
+func TestAdminReviewUpdateMarksRankingFailedWhenNotQueued(t *testing.T) {
+	repos := newTestRepos()
+	addTestMovie(t, repos.Movies, "tt1", "Heat")
+
+	queue := jobs.NewRankingQueue(nil, failingJobs{repository.NewMemoryRankingJobRepository()}, repos.Movies, 1, 1, nil)
+
+	router := newTestRouter()
+	router.PATCH("/updatereview/:imdb_id", AdminReviewUpdate(repos.Movies, queue, nil))
+
+	w := serve(t, router, http.MethodPatch, "/updatereview/tt1", gin.H{"admin_review": "Tense and brilliant"})
+	expectStatus(t, w, http.StatusInternalServerError)
+
+	movie, err := repos.Movies.Get(context.Background(), "tt1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if movie.AdminReview != "Tense and brilliant" || movie.RankingStatus != models.JobStatusFailed {
+		t.Fatalf("movie = %+v, want the review saved with a failed ranking_status", movie)
+	}
+}
@@ -0,0 +1,273 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// legacyPlaceholderValue is the ranking_value that used to mark the ranking
+// hidden from the AI before the excluded_from_ai flag existed.
+const legacyPlaceholderValue = 999
+
+// MigrateExcludedRankings flags the legacy 999 placeholder ranking as
+// excluded_from_ai so it keeps being skipped by the review ranker.
+func MigrateExcludedRankings(rankings repository.RankingRepository) error {
+	var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+	defer cancel()
+
+	return rankings.ExcludeFromAI(ctx, legacyPlaceholderValue)
+}
+
+// rankingValueParam parses the :ranking_value path parameter
+func rankingValueParam(c *gin.Context) (int, bool) {
+	value, err := strconv.Atoi(c.Param("ranking_value"))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ranking_value must be an integer"})
+		return 0, false
+	}
+
+	return value, true
+}
+
+// ListRankings handles GET /rankings requests
+// It returns the ranking scale ordered by ranking_value.
+func ListRankings(rankings repository.RankingRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		scale, err := rankings.List(ctx)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rankings"})
+			return
+		}
+
+		c.JSON(http.StatusOK, scale)
+	}
+}
+
+// CreateRanking handles POST /rankings requests
+func CreateRanking(rankings repository.RankingRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var ranking models.Ranking
+
+		if err := c.ShouldBindJSON(&ranking); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+
+		if err := validate.Struct(ranking); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrorMessages(err)})
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		if err := rankings.Insert(ctx, ranking); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.JSON(http.StatusConflict, gin.H{"error": "A ranking with this value or name already exists"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create ranking"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, ranking)
+	}
+}
+
+// UpdateRanking handles PUT /rankings/:ranking_value requests
+// Renaming or revaluing a ranking is propagated to the movies that carry it.
+func UpdateRanking(rankings repository.RankingRepository, movies repository.MovieRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		value, ok := rankingValueParam(c)
+
+		if !ok {
+			return
+		}
+
+		var ranking models.Ranking
+
+		if err := c.ShouldBindJSON(&ranking); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+
+		if err := validate.Struct(ranking); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrorMessages(err)})
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		previous, err := rankings.Replace(ctx, value, ranking)
+
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Ranking not found"})
+				return
+			}
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.JSON(http.StatusConflict, gin.H{"error": "A ranking with this value or name already exists"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update ranking"})
+			return
+		}
+
+		if err := movies.ReplaceRanking(ctx, previous, ranking); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ranking updated but movies could not be updated"})
+			return
+		}
+
+		c.JSON(http.StatusOK, ranking)
+	}
+}
+
+// ReorderRankings handles PUT /rankings/order requests
+// The body lists every ranking that is not excluded from the AI, best first;
+// they are renumbered 1..n and movies are updated to the new values.
+func ReorderRankings(rankings repository.RankingRepository, movies repository.MovieRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var req models.RankingOrder
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+
+		if err := validate.Struct(req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrorMessages(err)})
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		scale, err := rankings.List(ctx)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rankings"})
+			return
+		}
+
+		ranked := map[string]bool{}
+		excludedValues := map[int]bool{}
+
+		for _, ranking := range scale {
+			// reorder moves rankings through negative values, which a
+			// stored value below 1 would collide with
+			if ranking.RankingValue < 1 {
+				c.JSON(http.StatusConflict, gin.H{"error": "ranking " + ranking.RankingName + " has value " + strconv.Itoa(ranking.RankingValue) + "; update it to 1 or more first"})
+				return
+			}
+
+			if ranking.ExcludedFromAI {
+				excludedValues[ranking.RankingValue] = true
+			} else {
+				ranked[ranking.RankingName] = true
+			}
+		}
+
+		seen := map[string]bool{}
+
+		for _, name := range req.Order {
+			if !ranked[name] || seen[name] {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "order must list every ranked name exactly once: " + name})
+				return
+			}
+			seen[name] = true
+		}
+
+		if len(seen) != len(ranked) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "order must list every ranking that is not excluded from AI"})
+			return
+		}
+
+		for i := range req.Order {
+			if excludedValues[i+1] {
+				c.JSON(http.StatusConflict, gin.H{"error": "new value " + strconv.Itoa(i+1) + " is used by an excluded ranking"})
+				return
+			}
+		}
+
+		if err := rankings.Reorder(ctx, req.Order); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder rankings"})
+			return
+		}
+
+		values := make(map[string]int, len(req.Order))
+
+		for i, name := range req.Order {
+			values[name] = i + 1
+		}
+
+		if err := movies.SetRankingValues(ctx, values); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Rankings reordered but movies could not be updated"})
+			return
+		}
+
+		reordered, err := rankings.List(ctx)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rankings"})
+			return
+		}
+
+		c.JSON(http.StatusOK, reordered)
+	}
+}
+
+// DeleteRanking handles DELETE /rankings/:ranking_value requests
+// A ranking that is still used by a movie cannot be deleted.
+func DeleteRanking(rankings repository.RankingRepository, movies repository.MovieRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		value, ok := rankingValueParam(c)
+
+		if !ok {
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		inUse, err := movies.CountWithRanking(ctx, value)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check ranking usage"})
+			return
+		}
+
+		if inUse > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Ranking is used by " + strconv.FormatInt(inUse, 10) + " movies"})
+			return
+		}
+
+		if err := rankings.Delete(ctx, value); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Ranking not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete ranking"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+)
+
+func TestReorderRankingsRenumbersMovies(t *testing.T) {
+	repos := newTestRepos()
+	repos.Rankings = repository.NewMemoryRankingRepository(
+		models.Ranking{RankingValue: 1, RankingName: "Excellent"},
+		models.Ranking{RankingValue: 2, RankingName: "Good"},
+		models.Ranking{RankingValue: 999, RankingName: "Not_Ranked", ExcludedFromAI: true},
+	)
+
+	ctx := context.Background()
+
+	movie := &models.Movie{ImdbID: "tt1", Title: "Heat", Ranking: models.Ranking{RankingValue: 2, RankingName: "Good"}}
+
+	if err := repos.Movies.Insert(ctx, movie); err != nil {
+		t.Fatal(err)
+	}
+
+	router := newTestRouter()
+	router.PUT("/rankings/order", ReorderRankings(repos.Rankings, repos.Movies))
+
+	// excluded rankings cannot be reordered
+	w := serve(t, router, http.MethodPut, "/rankings/order", models.RankingOrder{Order: []string{"Good", "Not_Ranked", "Excellent"}})
+	expectStatus(t, w, http.StatusBadRequest)
+
+	w = serve(t, router, http.MethodPut, "/rankings/order", models.RankingOrder{Order: []string{"Good"}})
+	expectStatus(t, w, http.StatusBadRequest)
+
+	w = serve(t, router, http.MethodPut, "/rankings/order", models.RankingOrder{Order: []string{"Good", "Excellent"}})
+	expectStatus(t, w, http.StatusOK)
+
+	stored, err := repos.Movies.Get(ctx, "tt1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.Ranking.RankingValue != 1 {
+		t.Fatalf("movie ranking_value = %d, want 1", stored.Ranking.RankingValue)
+	}
+
+	var reordered []models.Ranking
+	decode(t, w, &reordered)
+
+	values := map[string]int{}
+
+	for _, ranking := range reordered {
+		values[ranking.RankingName] = ranking.RankingValue
+	}
+
+	if values["Good"] != 1 || values["Excellent"] != 2 || values["Not_Ranked"] != 999 {
+		t.Fatalf("rankings = %+v, want Good 1, Excellent 2 and Not_Ranked unchanged", reordered)
+	}
+}
+
+func TestRankingValuesStartAtOne(t *testing.T) {
+	repos := newTestRepos()
+	repos.Rankings = repository.NewMemoryRankingRepository(
+		models.Ranking{RankingValue: 1, RankingName: "Excellent"},
+		// stored before values had to be positive
+		models.Ranking{RankingValue: -1, RankingName: "Legacy"},
+	)
+
+	router := newTestRouter()
+	router.POST("/rankings", CreateRanking(repos.Rankings))
+	router.PUT("/rankings/order", ReorderRankings(repos.Rankings, repos.Movies))
+	router.PUT("/rankings/:ranking_value", UpdateRanking(repos.Rankings, repos.Movies))
+
+	expectStatus(t, serve(t, router, http.MethodPost, "/rankings", models.Ranking{RankingValue: -2, RankingName: "Bad"}), http.StatusBadRequest)
+	expectStatus(t, serve(t, router, http.MethodPut, "/rankings/1", models.Ranking{RankingValue: -2, RankingName: "Excellent"}), http.StatusBadRequest)
+
+	w := serve(t, router, http.MethodPut, "/rankings/order", models.RankingOrder{Order: []string{"Legacy", "Excellent"}})
+	expectStatus(t, w, http.StatusConflict)
+
+	expectStatus(t, serve(t, router, http.MethodPut, "/rankings/-1", models.Ranking{RankingValue: 2, RankingName: "Legacy"}), http.StatusOK)
+
+	w = serve(t, router, http.MethodPut, "/rankings/order", models.RankingOrder{Order: []string{"Legacy", "Excellent"}})
+	expectStatus(t, w, http.StatusOK)
+}
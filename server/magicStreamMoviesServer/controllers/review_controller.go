@@ -0,0 +1,241 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/recommend"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// refreshMovieRating recomputes a movie's average_rating and rating_count from its reviews
+func refreshMovieRating(ctx context.Context, movies repository.MovieRepository, reviews repository.ReviewRepository, imdbId string) error {
+	rating, err := reviews.Rating(ctx, imdbId)
+
+	if err != nil {
+		return err
+	}
+
+	err = movies.SetRating(ctx, imdbId, rating.Average, rating.Count)
+
+	// The movie may have been deleted together with its reviews
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// movieExists reports whether a movie with imdbId exists
+func movieExists(ctx context.Context, movies repository.MovieRepository, imdbId string) (bool, error) {
+	_, err := movies.Get(ctx, imdbId)
+
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// bindReviewInput binds and validates a rating/review body, responding with 400 on failure
+func bindReviewInput(c *gin.Context) (models.UserReviewInput, bool) {
+	var input models.UserReviewInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return input, false
+	}
+
+	if err := validate.Struct(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrorMessages(err)})
+		return input, false
+	}
+
+	return input, true
+}
+
+// CreateReview handles POST /movie/:imdb_id/reviews requests
+// It stores the authenticated user's rating and review; a user can only review a movie once.
+func CreateReview(movies repository.MovieRepository, reviews repository.ReviewRepository, recommender *recommend.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := utils.GetUserIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found in this context"})
+			return
+		}
+
+		input, ok := bindReviewInput(c)
+
+		if !ok {
+			return
+		}
+
+		imdbId := c.Param("imdb_id")
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		exists, err := movieExists(ctx, movies, imdbId)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch movie"})
+			return
+		}
+
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
+			return
+		}
+
+		now := time.Now()
+
+		review := models.UserReview{
+			UserID:    userId,
+			ImdbID:    imdbId,
+			Rating:    input.Rating,
+			Text:      input.Text,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		if err := reviews.Insert(ctx, &review); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.JSON(http.StatusConflict, gin.H{"error": "You already reviewed this movie"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save review"})
+			return
+		}
+
+		markFeedbackChanged(recommender, userId)
+
+		if err := refreshMovieRating(ctx, movies, reviews, imdbId); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Review saved but movie rating could not be updated"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, review)
+	}
+}
+
+// UpdateReview handles PUT /movie/:imdb_id/reviews/me requests
+// It edits the authenticated user's own rating and review.
+func UpdateReview(movies repository.MovieRepository, reviews repository.ReviewRepository, recommender *recommend.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := utils.GetUserIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found in this context"})
+			return
+		}
+
+		input, ok := bindReviewInput(c)
+
+		if !ok {
+			return
+		}
+
+		imdbId := c.Param("imdb_id")
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		review, err := reviews.Update(ctx, imdbId, userId, input)
+
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update review"})
+			return
+		}
+
+		markFeedbackChanged(recommender, userId)
+
+		if err := refreshMovieRating(ctx, movies, reviews, imdbId); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Review saved but movie rating could not be updated"})
+			return
+		}
+
+		c.JSON(http.StatusOK, review)
+	}
+}
+
+// DeleteReview handles DELETE /movie/:imdb_id/reviews/me requests
+func DeleteReview(movies repository.MovieRepository, reviews repository.ReviewRepository, recommender *recommend.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := utils.GetUserIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found in this context"})
+			return
+		}
+
+		imdbId := c.Param("imdb_id")
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		if err := reviews.Delete(ctx, imdbId, userId); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete review"})
+			return
+		}
+
+		markFeedbackChanged(recommender, userId)
+
+		if err := refreshMovieRating(ctx, movies, reviews, imdbId); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Review deleted but movie rating could not be updated"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetMovieReviews handles GET /movie/:imdb_id/reviews requests
+// It returns a page of the movie's reviews, newest first.
+func GetMovieReviews(reviews repository.ReviewRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		page, limit, ok := pageParams(c)
+
+		if !ok {
+			return
+		}
+
+		imdbId := c.Param("imdb_id")
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		total, err := reviews.CountByMovie(ctx, imdbId)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count reviews"})
+			return
+		}
+
+		movieReviews, err := reviews.ListByMovie(ctx, imdbId, (page-1)*limit, limit)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviews"})
+			return
+		}
+
+		c.JSON(http.StatusOK, models.UserReviewPage{Reviews: movieReviews, Total: total, Page: page, Limit: limit})
+	}
+}
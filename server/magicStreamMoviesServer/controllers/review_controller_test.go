@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+func TestReviewLifecycleUpdatesMovieRating(t *testing.T) {
+	repos := newTestRepos()
+	addTestMovie(t, repos.Movies, "tt1", "Heat")
+
+	router := newTestRouter()
+	router.POST("/movie/:imdb_id/reviews", CreateReview(repos.Movies, repos.Reviews, nil))
+	router.PUT("/movie/:imdb_id/reviews/me", UpdateReview(repos.Movies, repos.Reviews, nil))
+	router.DELETE("/movie/:imdb_id/reviews/me", DeleteReview(repos.Movies, repos.Reviews, nil))
+	router.GET("/movie/:imdb_id/reviews", GetMovieReviews(repos.Reviews))
+
+	expectRating := func(average float64, count int) {
+		t.Helper()
+
+		movie, err := repos.Movies.Get(context.Background(), "tt1")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if movie.AverageRating != average || movie.RatingCount != count {
+			t.Fatalf("rating = %v over %d reviews, want %v over %d", movie.AverageRating, movie.RatingCount, average, count)
+		}
+	}
+
+	w := serve(t, router, http.MethodPost, "/movie/tt1/reviews", models.UserReviewInput{Rating: 4, Text: "Tense"})
+	expectStatus(t, w, http.StatusCreated)
+	expectRating(4, 1)
+
+	w = serve(t, router, http.MethodPost, "/movie/tt1/reviews", models.UserReviewInput{Rating: 2})
+	expectStatus(t, w, http.StatusConflict)
+
+	w = serve(t, router, http.MethodPost, "/movie/tt9/reviews", models.UserReviewInput{Rating: 2})
+	expectStatus(t, w, http.StatusNotFound)
+
+	w = serve(t, router, http.MethodPut, "/movie/tt1/reviews/me", models.UserReviewInput{Rating: 2, Text: "Too long"})
+	expectStatus(t, w, http.StatusOK)
+	expectRating(2, 1)
+
+	w = serve(t, router, http.MethodGet, "/movie/tt1/reviews", nil)
+	expectStatus(t, w, http.StatusOK)
+
+	var page models.UserReviewPage
+	decode(t, w, &page)
+
+	if page.Total != 1 || len(page.Reviews) != 1 || page.Reviews[0].Text != "Too long" {
+		t.Fatalf("reviews page = %+v, want the updated review", page)
+	}
+
+	expectStatus(t, serve(t, router, http.MethodDelete, "/movie/tt1/reviews/me", nil), http.StatusNoContent)
+	expectRating(0, 0)
+
+	expectStatus(t, serve(t, router, http.MethodDelete, "/movie/tt1/reviews/me", nil), http.StatusNotFound)
+	expectStatus(t, serve(t, router, http.MethodPut, "/movie/tt1/reviews/me", models.UserReviewInput{Rating: 3}), http.StatusNotFound)
+}
+
+func TestCreateReviewValidatesRating(t *testing.T) {
+	repos := newTestRepos()
+	addTestMovie(t, repos.Movies, "tt1", "Heat")
+
+	router := newTestRouter()
+	router.POST("/movie/:imdb_id/reviews", CreateReview(repos.Movies, repos.Reviews, nil))
+
+	w := serve(t, router, http.MethodPost, "/movie/tt1/reviews", models.UserReviewInput{Rating: 6})
+	expectStatus(t, w, http.StatusBadRequest)
+}
@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"context"
+	"html"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// fuzzyCandidateLimit caps how many prefix matches are scored in Go
+	fuzzyCandidateLimit = 200
+	// fuzzyMinSimilarity is the trigram similarity a fuzzy hit must reach
+	fuzzyMinSimilarity = 0.2
+)
+
+// SearchMovies handles GET /movies/search?q= requests
+// It runs a text search over title and admin_review ordered by relevance. When that finds nothing (typically because of a typo) it falls
+// back to prefix matching on title words scored by trigram similarity.
+func SearchMovies(movies repository.MovieRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		query := strings.TrimSpace(c.Query("q"))
+
+		if query == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter q is required"})
+			return
+		}
+
+		limit, err := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+
+		if err != nil || limit < 1 || limit > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		terms := searchTerms(query)
+
+		results, err := movies.TextSearch(ctx, query, limit)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search movies"})
+			return
+		}
+
+		fallback := false
+
+		if len(results) == 0 {
+			fallback = true
+			results, err = fuzzySearchMovies(ctx, movies, query, terms, limit)
+
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search movies"})
+				return
+			}
+		}
+
+		for i := range results {
+			results[i].Highlights = highlightMovie(results[i].Movie, terms)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"query":    query,
+			"fallback": fallback,
+			"results":  results,
+		})
+	}
+}
+
+// fuzzySearchMovies fetches movies with a title word starting with the first
+// characters of any query term and ranks them by trigram similarity to the query.
+func fuzzySearchMovies(ctx context.Context, movies repository.MovieRepository, query string, terms []string, limit int64) ([]models.MovieSearchResult, error) {
+	prefixes := make([]string, 0, len(terms))
+
+	for _, term := range terms {
+		prefix := []rune(term)
+		if len(prefix) > 3 {
+			prefix = prefix[:3]
+		}
+		prefixes = append(prefixes, string(prefix))
+	}
+
+	candidates, err := movies.ListByTitleWordPrefix(ctx, prefixes, fuzzyCandidateLimit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := []models.MovieSearchResult{}
+
+	queryGrams := trigrams(query)
+
+	for _, movie := range candidates {
+		similarity := trigramSimilarity(queryGrams, trigrams(movie.Title))
+
+		// also compare against individual title words so one matching word in
+		// a long title is not drowned out by the rest of it
+		for _, word := range searchTerms(movie.Title) {
+			for _, term := range terms {
+				if s := trigramSimilarity(trigrams(term), trigrams(word)); s > similarity {
+					similarity = s
+				}
+			}
+		}
+
+		if similarity >= fuzzyMinSimilarity {
+			results = append(results, models.MovieSearchResult{Movie: movie, Score: similarity})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	if int64(len(results)) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// searchTerms splits s into lower-cased words
+func searchTerms(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// trigrams returns the set of padded character trigrams of every word in s
+func trigrams(s string) map[string]bool {
+	grams := map[string]bool{}
+
+	for _, word := range searchTerms(s) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			grams[string(padded[i:i+3])] = true
+		}
+	}
+
+	return grams
+}
+
+// trigramSimilarity is the Jaccard index of two trigram sets
+func trigramSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for gram := range a {
+		if b[gram] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// highlightMovie wraps words starting with any search term in <mark> tags.
+// The field text is HTML-escaped so only the tags added here are markup.
+// Only fields that contain at least one match are returned.
+func highlightMovie(movie models.Movie, terms []string) map[string]string {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		quoted = append(quoted, regexp.QuoteMeta(term))
+	}
+
+	pattern := regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\w*`)
+
+	highlights := map[string]string{}
+
+	for field, value := range map[string]string{"title": movie.Title, "admin_review": movie.AdminReview} {
+		if marked, ok := markMatches(value, pattern); ok {
+			highlights[field] = marked
+		}
+	}
+
+	return highlights
+}
+
+// markMatches HTML-escapes value and wraps every match of pattern in <mark>
+// tags. Matching runs on the raw text so escaped entities are never matched.
+func markMatches(value string, pattern *regexp.Regexp) (string, bool) {
+	matches := pattern.FindAllStringIndex(value, -1)
+
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	var b strings.Builder
+	last := 0
+
+	for _, match := range matches {
+		b.WriteString(html.EscapeString(value[last:match[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(value[match[0]:match[1]]))
+		b.WriteString("</mark>")
+		last = match[1]
+	}
+
+	b.WriteString(html.EscapeString(value[last:]))
+
+	return b.String(), true
+}
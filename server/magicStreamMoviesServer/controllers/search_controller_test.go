@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+type searchResponse struct {
+	Fallback bool                       `json:"fallback"`
+	Results  []models.MovieSearchResult `json:"results"`
+}
+
+func TestSearchMovies(t *testing.T) {
+	repos := newTestRepos()
+	addTestMovie(t, repos.Movies, "tt1", "The Godfather")
+	addTestMovie(t, repos.Movies, "tt2", "Heat")
+	addTestMovie(t, repos.Movies, "tt3", "Godzilla <1954>")
+
+	router := newTestRouter()
+	router.GET("/movies/search", SearchMovies(repos.Movies))
+
+	w := serve(t, router, http.MethodGet, "/movies/search?q=godfather", nil)
+	expectStatus(t, w, http.StatusOK)
+
+	var exact searchResponse
+	decode(t, w, &exact)
+
+	if exact.Fallback || len(exact.Results) != 1 || exact.Results[0].Movie.ImdbID != "tt1" {
+		t.Fatalf("search godfather = %+v, want tt1 from the text search", exact)
+	}
+
+	if got := exact.Results[0].Highlights["title"]; got != "The <mark>Godfather</mark>" {
+		t.Fatalf("title highlight = %q", got)
+	}
+
+	// a typo finds nothing in the text search and falls back to trigrams
+	w = serve(t, router, http.MethodGet, "/movies/search?q=godfahter", nil)
+	expectStatus(t, w, http.StatusOK)
+
+	var fuzzy searchResponse
+	decode(t, w, &fuzzy)
+
+	if !fuzzy.Fallback || len(fuzzy.Results) == 0 || fuzzy.Results[0].Movie.ImdbID != "tt1" {
+		t.Fatalf("search godfahter = %+v, want tt1 first from the fallback", fuzzy)
+	}
+
+	expectStatus(t, serve(t, router, http.MethodGet, "/movies/search", nil), http.StatusBadRequest)
+	expectStatus(t, serve(t, router, http.MethodGet, "/movies/search?q=heat&limit=0", nil), http.StatusBadRequest)
+}
+
+func TestHighlightMovieEscapesHTML(t *testing.T) {
+	movie := models.Movie{Title: "Godzilla <1954>", AdminReview: "Not about gods"}
+
+	highlights := highlightMovie(movie, searchTerms("god"))
+
+	if got := highlights["title"]; got != "<mark>Godzilla</mark> &lt;1954&gt;" {
+		t.Fatalf("title highlight = %q", got)
+	}
+
+	if got := highlights["admin_review"]; got != "Not about <mark>gods</mark>" {
+		t.Fatalf("admin_review highlight = %q", got)
+	}
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/embedder"
+	"github.com/gin-gonic/gin"
+)
+
+// markMovieTextChanged asks the embedding index, when configured, to re-embed
+// the given movies, or every movie when no imdb ids are given
+func markMovieTextChanged(similarIndex *embedder.Index, imdbIds ...string) {
+	if similarIndex != nil {
+		similarIndex.Notify(imdbIds...)
+	}
+}
+
+// GetSimilarMovies handles GET /movie/:imdb_id/similar requests
+// It returns the movies whose embeddings are nearest to the movie's, with their cosine similarity.
+// A nil similarIndex answers 503.
+func GetSimilarMovies(similarIndex *embedder.Index) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		if similarIndex == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Similar movie search is not configured"})
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+		if err != nil || limit < 1 || limit > 50 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 50"})
+			return
+		}
+
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		similar, err := similarIndex.Similar(ctx, c.Param("imdb_id"), limit)
+
+		if err != nil {
+			if errors.Is(err, embedder.ErrMovieNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find similar movies"})
+			return
+		}
+
+		c.JSON(http.StatusOK, similar)
+	}
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/embedder"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+func TestGetSimilarMovies(t *testing.T) {
+	repos := newTestRepos()
+
+	movies := []models.Movie{
+		{ImdbID: "tt1", Title: "Heat", AdminReview: "A tense crime thriller about a bank heist crew"},
+		{ImdbID: "tt2", Title: "Thief", AdminReview: "A crime thriller about a safecracker planning one last heist"},
+		{ImdbID: "tt3", Title: "Babe", AdminReview: "A gentle family film about a talking pig on a farm"},
+	}
+
+	for i := range movies {
+		if err := repos.Movies.Insert(context.Background(), &movies[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	index := embedder.NewIndex(embedder.NewLocalEmbedder(embedder.DefaultLocalDimensions), repos.Movies, repos.Embeddings, 0)
+
+	if err := index.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	router := newTestRouter()
+	router.GET("/movie/:imdb_id/similar", GetSimilarMovies(index))
+
+	w := serve(t, router, http.MethodGet, "/movie/tt1/similar", nil)
+	expectStatus(t, w, http.StatusOK)
+
+	var similar []models.SimilarMovie
+	decode(t, w, &similar)
+
+	if len(similar) != 2 || similar[0].Movie.ImdbID != "tt2" || similar[1].Movie.ImdbID != "tt3" {
+		t.Fatalf("similar = %+v, want tt2 then tt3 without tt1 itself", similar)
+	}
+
+	if similar[0].Score <= similar[1].Score {
+		t.Fatalf("scores %f and %f are not in descending order", similar[0].Score, similar[1].Score)
+	}
+
+	w = serve(t, router, http.MethodGet, "/movie/tt1/similar?limit=1", nil)
+	expectStatus(t, w, http.StatusOK)
+	decode(t, w, &similar)
+
+	if len(similar) != 1 || similar[0].Movie.ImdbID != "tt2" {
+		t.Fatalf("limit=1 gave %+v, want only tt2", similar)
+	}
+
+	expectStatus(t, serve(t, router, http.MethodGet, "/movie/tt9/similar", nil), http.StatusNotFound)
+	expectStatus(t, serve(t, router, http.MethodGet, "/movie/tt1/similar?limit=0", nil), http.StatusBadRequest)
+}
+
+func TestGetSimilarMoviesNotConfigured(t *testing.T) {
+	router := newTestRouter()
+	router.GET("/movie/:imdb_id/similar", GetSimilarMovies(nil))
+
+	expectStatus(t, serve(t, router, http.MethodGet, "/movie/tt1/similar", nil), http.StatusServiceUnavailable)
+}
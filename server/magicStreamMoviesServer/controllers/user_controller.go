@@ -2,11 +2,13 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
-	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/database"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/config"
 	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
 	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -15,8 +17,6 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-var userCollection *mongo.Collection = database.OpenCollection("users")
-
 func HashPassword(password string) (string, error) {
 
 	HashPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -28,7 +28,7 @@ func HashPassword(password string) (string, error) {
 	return string(HashPassword), nil
 }
 
-func RegisterUser() gin.HandlerFunc {
+func RegisterUser(users repository.UserRepository, genreRepo repository.GenreRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var user models.User
 
@@ -37,6 +37,13 @@ func RegisterUser() gin.HandlerFunc {
 			return
 		}
 
+		// Self-registration always creates a regular, enabled account; the
+		// role and tokens are never taken from the request body.
+		user.Role = "USER"
+		user.Disabled = false
+		user.Token = ""
+		user.RefreshToken = ""
+
 		//validate := validator.New()
 
 		if err := validate.Struct(user); err != nil {
@@ -61,35 +68,34 @@ func RegisterUser() gin.HandlerFunc {
 		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
 		defer cancel()
 
-		count, err := userCollection.CountDocuments(ctx, bson.M{"email": user.Email})
-
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing user"})
-			return
-		}
+		// Favourite genres must reference the genres collection; store the canonical names
+		genres, ok := checkGenres(c, ctx, genreRepo, user.FavouriteGenres)
 
-		if count > 0 {
-			c.JSON(http.StatusConflict, gin.H{"error": "User already exists"})
+		if !ok {
 			return
 		}
 
+		user.FavouriteGenres = genres
 		user.UserID = bson.NewObjectID().Hex()
 		user.Password = hashedPassword
 		user.CreatedAt = time.Now()
 		user.UpdatedAt = time.Now()
 
-		result, err := userCollection.InsertOne(ctx, user)
-
-		if err != nil {
+		// The unique email index rejects concurrent registrations of the same address
+		if err := users.Insert(ctx, &user); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.JSON(http.StatusConflict, gin.H{"error": "User already exists"})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
 			return
 		}
 
-		c.JSON(http.StatusCreated, result)
+		c.JSON(http.StatusCreated, gin.H{"InsertedID": user.ID})
 	}
 }
 
-func LoginUser() gin.HandlerFunc {
+func LoginUser(users repository.UserRepository, auth config.AuthConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
 		var userLogin models.UserLogin
@@ -102,9 +108,7 @@ func LoginUser() gin.HandlerFunc {
 		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
 		defer cancel()
 
-		var foundUser models.User
-
-		err := userCollection.FindOne(ctx, bson.M{"email": userLogin.Email}).Decode(&foundUser)
+		foundUser, err := users.GetByEmail(ctx, userLogin.Email)
 
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email"})
@@ -118,16 +122,24 @@ func LoginUser() gin.HandlerFunc {
 			return
 		}
 
+		if foundUser.Disabled {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+			return
+		}
+
 		//c.JSON(http.StatusOK, gin.H{"message": "Good Login"})
 
-		token, refreshToken, err := utils.GenerateAllTokens(foundUser.Email, foundUser.FirstName, foundUser.LastName, foundUser.Role, foundUser.UserID)
+		// Every login starts its own token family so other devices stay signed in
+		familyId := utils.NewTokenFamilyId()
+
+		token, refreshToken, err := utils.GenerateAllTokens(auth, foundUser.Email, foundUser.FirstName, foundUser.LastName, foundUser.Role, foundUser.UserID, familyId)
 
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate tokens"})
 			return
 		}
 
-		err = utils.UpdateAllTokens(foundUser.UserID, token, refreshToken)
+		err = utils.UpdateAllTokens(foundUser.UserID, familyId, token, refreshToken)
 
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update toknens "})
@@ -146,3 +158,123 @@ func LoginUser() gin.HandlerFunc {
 		})
 	}
 }
+
+// RefreshToken handles POST /refresh requests
+// It exchanges a valid refresh token for a new access/refresh pair and rotates
+// the refresh token of its session. Presenting a token that was already rotated
+// revokes that session only.
+func RefreshToken(auth config.AuthConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var req models.RefreshTokenRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+			return
+		}
+
+		if err := validate.Struct(req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Refresh token is required"})
+			return
+		}
+
+		claims, token, refreshToken, err := utils.RotateRefreshToken(auth, req.RefreshToken)
+
+		if err != nil {
+			if errors.Is(err, utils.ErrRefreshTokenReused) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token reuse detected, please login again"})
+				return
+			}
+			if errors.Is(err, utils.ErrAccountDisabled) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+				return
+			}
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+				return
+			}
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+			return
+		}
+
+		c.JSON(http.StatusOK, models.UserResponse{
+			UserId:       claims.UserId,
+			FirstName:    claims.FirstName,
+			LastName:     claims.LastName,
+			Email:        claims.Email,
+			Role:         claims.Role,
+			Token:        token,
+			RefreshToken: refreshToken,
+		})
+	}
+}
+
+// Logout handles POST /logout requests
+// It revokes the access token used for the request and ends its token family
+// so the current session cannot be refreshed. Other sessions stay signed in.
+func Logout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := utils.GetUserIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found in this context"})
+			return
+		}
+
+		tokenId, err := utils.GetTokenIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Token id not found in this context"})
+			return
+		}
+
+		familyId, err := utils.GetTokenFamilyIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Token family not found in this context"})
+			return
+		}
+
+		expiresAt, err := utils.GetTokenExpiryFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Token expiry not found in this context"})
+			return
+		}
+
+		if err := utils.RevokeToken(tokenId, userId, expiresAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke token"})
+			return
+		}
+
+		if err := utils.RevokeTokenFamily(userId, familyId); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to end session"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+	}
+}
+
+// LogoutAll handles POST /logout/all requests
+// It revokes every token issued to the authenticated user so all sessions
+// on every device are logged out.
+func LogoutAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := utils.GetUserIdFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User id not found in this context"})
+			return
+		}
+
+		if err := utils.RevokeAllUserTokens(userId); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke tokens"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Logged out from all sessions"})
+	}
+}
@@ -1,73 +1,125 @@
 package database
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
-	"os"
+	"time"
 
-	"github.com/joho/godotenv"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/config"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 )
 
-// DBInstance creates and return MongoDB client instance
-// It loads env variables and establishes the database connection.
-func DBInstance() *mongo.Client {
+// Backoff between connection attempts: it starts at retryBackoff and doubles up to maxRetryBackoff
+const (
+	retryBackoff    = time.Second
+	maxRetryBackoff = 30 * time.Second
+)
 
-	// Load env variables from env file
-	err := godotenv.Load(".env")
+// Client is the MongoDB client created by Connect
+var Client *mongo.Client
 
-	if err != nil {
-		log.Println("Warning: unable to find .env file")
-	}
+var databaseName string
 
-	// Get MongoDB connection URI from env variables
-	MongoDb := os.Getenv("MONGODB_URI")
+// Connect creates the MongoDB client and pings the server, retrying with
+// backoff while MongoDB is starting. It must be called before any collection
+// is used.
+func Connect(ctx context.Context, cfg config.DatabaseConfig) error {
+	if cfg.URI == "" {
+		return errors.New("MONGODB_URI not set")
+	}
 
-	if MongoDb == "" {
-		log.Fatal("MONGO_URI not set!")
+	if cfg.Name == "" {
+		return errors.New("DATABASE_NAME not set")
 	}
 
-	fmt.Println("MongoDB  URI: ", MongoDb)
+	clientOptions := options.Client().
+		ApplyURI(cfg.URI).
+		SetConnectTimeout(time.Duration(cfg.ConnectTimeoutSeconds) * time.Second).
+		SetServerSelectionTimeout(time.Duration(cfg.ServerSelectionTimeoutSeconds) * time.Second).
+		SetMaxPoolSize(uint64(cfg.MaxPoolSize))
 
-	// Apply the MongoDB connection URI to client options
-	clientOptions := options.Client().ApplyURI(MongoDb)
+	attempts := max(cfg.ConnectAttempts, 1)
+	backoff := retryBackoff
 
-	// Establish connection to MongoDB
-	client, err := mongo.Connect(clientOptions)
+	var err error
 
-	if err != nil {
-		return nil
+	for attempt := 1; attempt <= attempts; attempt++ {
+		var client *mongo.Client
+
+		client, err = connectAndPing(ctx, clientOptions)
+
+		if err == nil {
+			Client = client
+			databaseName = cfg.Name
+			return nil
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		log.Printf("MongoDB not reachable (attempt %d/%d): %v; retrying in %s", attempt, attempts, err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, maxRetryBackoff)
 	}
 
-	return client
+	return fmt.Errorf("connecting to MongoDB after %d attempts: %w", attempts, err)
 }
 
-var Client *mongo.Client = DBInstance()
-
-// OpenCollection returns a reference to specific MongoDB collection
-// It reads the database name from env variables
-func OpenCollection(collectionName string) *mongo.Collection {
-	err := godotenv.Load(".env")
+// connectAndPing creates a client and checks that the server answers
+func connectAndPing(ctx context.Context, clientOptions *options.ClientOptions) (*mongo.Client, error) {
+	client, err := mongo.Connect(clientOptions)
 
 	if err != nil {
-		log.Println("Warning: unable to find .env file")
+		return nil, err
 	}
 
-	// Get database name from env variables
-	databaseName := os.Getenv("DATABASE_NAME")
-	if databaseName == "" {
-		log.Fatal("DATABASE_NAME not set!")
+	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, err
 	}
 
-	fmt.Println("DATABASE_NAME: ", databaseName)
+	return client, nil
+}
 
-	// Return the requested collection
-	collection := Client.Database(databaseName).Collection(collectionName)
+// Ping checks that MongoDB is reachable through the connected client
+func Ping(ctx context.Context) error {
+	if Client == nil {
+		return errors.New("database is not connected")
+	}
 
-	if collection == nil {
+	return Client.Ping(ctx, readpref.Primary())
+}
+
+// Disconnect closes the client's connections once in-flight operations finish
+func Disconnect(ctx context.Context) error {
+	if Client == nil {
 		return nil
 	}
 
-	return collection
+	return Client.Disconnect(ctx)
+}
+
+// OpenDatabase returns a reference to the application database
+func OpenDatabase() *mongo.Database {
+	if Client == nil {
+		panic("database: Connect must be called before opening the database")
+	}
+
+	return Client.Database(databaseName)
+}
+
+// OpenCollection returns a reference to specific MongoDB collection
+func OpenCollection(collectionName string) *mongo.Collection {
+	return OpenDatabase().Collection(collectionName)
 }
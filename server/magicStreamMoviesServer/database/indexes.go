@@ -0,0 +1,265 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// requiredIndexes lists, per collection, the indexes the application relies on.
+// Every index is named so it can be verified after creation.
+var requiredIndexes = map[string][]mongo.IndexModel{
+	"genres": {
+		{
+			Keys:    bson.D{{Key: "genre_id", Value: 1}},
+			Options: options.Index().SetName("genre_id_unique").SetUnique(true),
+		},
+		{
+			// name_key is the spelling-insensitive form of genre_name
+			Keys:    bson.D{{Key: "name_key", Value: 1}},
+			Options: options.Index().SetName("name_key_unique").SetUnique(true),
+		},
+	},
+	"interactions": {
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "imdb_id", Value: 1}},
+			Options: options.Index().SetName("user_id_imdb_id_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "imdb_id", Value: 1}},
+			Options: options.Index().SetName("imdb_id"),
+		},
+	},
+	"movie_embeddings": {
+		{
+			Keys:    bson.D{{Key: "imdb_id", Value: 1}, {Key: "model", Value: 1}},
+			Options: options.Index().SetName("imdb_id_model_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "model", Value: 1}},
+			Options: options.Index().SetName("model"),
+		},
+	},
+	"movie_similarities": {
+		{
+			Keys:    bson.D{{Key: "imdb_id", Value: 1}},
+			Options: options.Index().SetName("imdb_id_unique").SetUnique(true),
+		},
+		{
+			// finds the movies that list a given movie as a neighbour
+			Keys:    bson.D{{Key: "neighbours.imdb_id", Value: 1}},
+			Options: options.Index().SetName("neighbours_imdb_id"),
+		},
+	},
+	"movies": {
+		{
+			Keys:    bson.D{{Key: "imdb_id", Value: 1}},
+			Options: options.Index().SetName("imdb_id_unique").SetUnique(true),
+		},
+		{
+			// text index used by the movie search endpoint; title matches weigh more
+			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "admin_review", Value: "text"}},
+			Options: options.Index().
+				SetName("movie_text_search").
+				SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "admin_review", Value: 2}}),
+		},
+	},
+	"watch_history": {
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "imdb_id", Value: 1}},
+			Options: options.Index().SetName("user_id_imdb_id_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "updated_at", Value: -1}},
+			Options: options.Index().SetName("user_id_updated_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "imdb_id", Value: 1}},
+			Options: options.Index().SetName("imdb_id"),
+		},
+	},
+	"user_lists": {
+		{
+			// a movie appears at most once in each of a user's lists
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "list", Value: 1}, {Key: "imdb_id", Value: 1}},
+			Options: options.Index().SetName("user_id_list_imdb_id_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "imdb_id", Value: 1}},
+			Options: options.Index().SetName("imdb_id"),
+		},
+	},
+	"users": {
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetName("email_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("user_id_unique").SetUnique(true),
+		},
+	},
+	"rankings": {
+		{
+			Keys:    bson.D{{Key: "ranking_value", Value: 1}},
+			Options: options.Index().SetName("ranking_value_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "ranking_name", Value: 1}},
+			Options: options.Index().SetName("ranking_name_unique").SetUnique(true),
+		},
+	},
+	"ranking_jobs": {
+		{
+			// used by the ranking queue sweeper to find due jobs
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+			Options: options.Index().SetName("status_next_attempt_at"),
+		},
+		{
+			// used by the sweeper to release jobs whose worker stopped
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "lease_expires_at", Value: 1}},
+			Options: options.Index().SetName("status_lease_expires_at"),
+		},
+	},
+	"rerank_diffs": {
+		{
+			Keys:    bson.D{{Key: "run_id", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName("run_id__id"),
+		},
+	},
+	"rerank_failures": {
+		{
+			Keys:    bson.D{{Key: "run_id", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName("run_id__id"),
+		},
+	},
+	"reviews": {
+		{
+			// one review per user and movie
+			Keys:    bson.D{{Key: "imdb_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("imdb_id_user_id_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "imdb_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("imdb_id_created_at"),
+		},
+	},
+	"revoked_tokens": {
+		{
+			// TTL index: denylist entries disappear once the tokens they cover have expired
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+		},
+		{
+			Keys:    bson.D{{Key: "jti", Value: 1}},
+			Options: options.Index().SetName("jti").SetSparse(true),
+		},
+	},
+	"token_families": {
+		{
+			Keys:    bson.D{{Key: "family_id", Value: 1}},
+			Options: options.Index().SetName("family_id_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("user_id"),
+		},
+		{
+			// TTL index: a family disappears once its refresh token can no longer be used
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+		},
+	},
+}
+
+// obsoleteIndexes lists, per collection, indexes that are no longer used or are
+// replaced by one in requiredIndexes and must be dropped first because they cover the same keys.
+var obsoleteIndexes = map[string][]string{
+	"rankings": {"ranking_value"},
+}
+
+// EnsureIndexes creates every required index and then verifies that each one
+// exists with the expected uniqueness. It fails if an index cannot be built,
+// for example because duplicate values are already stored in a unique field.
+func EnsureIndexes(ctx context.Context) error {
+	for collectionName, models := range requiredIndexes {
+		collection := OpenCollection(collectionName)
+
+		if err := dropObsoleteIndexes(ctx, collection, obsoleteIndexes[collectionName]); err != nil {
+			return err
+		}
+
+		if _, err := collection.Indexes().CreateMany(ctx, models); err != nil {
+			return fmt.Errorf("creating indexes on %s: %w", collectionName, err)
+		}
+
+		if err := verifyIndexes(ctx, collection, models); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dropObsoleteIndexes drops the named indexes if they still exist
+func dropObsoleteIndexes(ctx context.Context, collection *mongo.Collection, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	specs, err := collection.Indexes().ListSpecifications(ctx)
+
+	if err != nil {
+		return fmt.Errorf("listing indexes on %s: %w", collection.Name(), err)
+	}
+
+	for _, spec := range specs {
+		if !slices.Contains(names, spec.Name) {
+			continue
+		}
+
+		if err := collection.Indexes().DropOne(ctx, spec.Name); err != nil {
+			return fmt.Errorf("dropping index %s on %s: %w", spec.Name, collection.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// verifyIndexes checks that every expected index exists on the collection
+func verifyIndexes(ctx context.Context, collection *mongo.Collection, expected []mongo.IndexModel) error {
+	specs, err := collection.Indexes().ListSpecifications(ctx)
+
+	if err != nil {
+		return fmt.Errorf("listing indexes on %s: %w", collection.Name(), err)
+	}
+
+	existing := make(map[string]bool, len(specs))
+
+	for _, spec := range specs {
+		existing[spec.Name] = spec.Unique != nil && *spec.Unique
+	}
+
+	for _, model := range expected {
+		var opts options.IndexOptions
+		for _, set := range model.Options.List() {
+			_ = set(&opts)
+		}
+
+		unique, ok := existing[*opts.Name]
+
+		if !ok {
+			return fmt.Errorf("index %s missing on %s", *opts.Name, collection.Name())
+		}
+
+		if wantUnique := opts.Unique != nil && *opts.Unique; wantUnique != unique {
+			return fmt.Errorf("index %s on %s has unexpected uniqueness", *opts.Name, collection.Name())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package embedder
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/config"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+// Embedder turns texts into vectors
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Model identifies the vector space; vectors of different models are never compared
+	Model() string
+}
+
+// FromConfig builds the Embedder selected by the embedding provider (EMBEDDING_PROVIDER):
+//
+//	local  - offline feature-hashing embedder; the default
+//	openai - OpenAI embeddings (OPENAI_API_KEY, optional OPENAI_EMBEDDING_MODEL)
+//	ollama - Ollama-compatible HTTP endpoint (OLLAMA_SERVER_URL, OLLAMA_EMBEDDING_MODEL)
+func FromConfig(cfg *config.Config) (Embedder, error) {
+	switch provider := cfg.Embedding.Provider; provider {
+	case "", "local":
+		return NewLocalEmbedder(DefaultLocalDimensions), nil
+	case "openai":
+		return NewOpenAIEmbedder(cfg.OpenAI.APIKey, cfg.OpenAI.EmbeddingModel)
+	case "ollama":
+		return NewOllamaEmbedder(cfg.Ollama.ServerURL, cfg.Ollama.EmbeddingModel)
+	default:
+		return nil, fmt.Errorf("unknown EMBEDDING_PROVIDER %q", provider)
+	}
+}
+
+// MovieText is the text embedded for a movie: its title, genres and admin review
+func MovieText(movie models.Movie) string {
+	genres := make([]string, 0, len(movie.Genre))
+
+	for _, genre := range movie.Genre {
+		genres = append(genres, genre.GenreName)
+	}
+
+	return fmt.Sprintf("Title: %s\nGenres: %s\nReview: %s", movie.Title, strings.Join(genres, ", "), movie.AdminReview)
+}
+
+// Cosine returns the cosine similarity of a and b, or 0 when their lengths differ
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,512 @@
+package embedder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+)
+
+// ErrMovieNotFound is returned by Similar for unknown imdb ids
+var ErrMovieNotFound = errors.New("movie not found")
+
+// batchSize is how many movies are embedded per call to the embedder
+const batchSize = 32
+
+// syncPageSize is how many movies a full sync loads at a time
+const syncPageSize = 500
+
+// deleteBatchSize is how many embeddings of deleted movies are removed per write
+const deleteBatchSize = 500
+
+// cachedVector is a stored embedding kept in memory with the hash of its text
+type cachedVector struct {
+	textHash string
+	vector   []float32
+}
+
+// Index keeps an embedding per movie in an EmbeddingRepository and answers
+// nearest-neighbour queries over them. Every interval a full sync pages
+// through the movies; movies passed to Notify are re-embedded as soon as
+// possible without a full sync.
+type Index struct {
+	embedder   Embedder
+	movies     repository.MovieRepository
+	embeddings repository.EmbeddingRepository
+	interval   time.Duration
+
+	// changed holds the imdb ids passed to Notify since the last sync;
+	// changedAll asks for a full sync instead
+	changedMu  sync.Mutex
+	changed    map[string]bool
+	changedAll bool
+
+	// vectors caches the embeddings of the current model by imdb_id so
+	// Similar does not load them on every request. It is nil until the first
+	// Similar and then kept up to date by the syncs.
+	vectorsMu sync.RWMutex
+	vectors   map[string]cachedVector
+
+	notify chan struct{}
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+}
+
+// NewIndex creates an Index that embeds the movies of movies with embedder
+// and stores the vectors in embeddings
+func NewIndex(embedder Embedder, movies repository.MovieRepository, embeddings repository.EmbeddingRepository, interval time.Duration) *Index {
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+
+	return &Index{
+		embedder:   embedder,
+		movies:     movies,
+		embeddings: embeddings,
+		interval:   interval,
+		changed:    map[string]bool{},
+		notify:     make(chan struct{}, 1),
+	}
+}
+
+// Start launches the background sync, which first runs a full sync immediately
+func (x *Index) Start(ctx context.Context) {
+	ctx, x.cancel = context.WithCancel(ctx)
+
+	x.wg.Add(1)
+
+	go func() {
+		defer x.wg.Done()
+
+		ticker := time.NewTicker(x.interval)
+		defer ticker.Stop()
+
+		err := x.Sync(ctx)
+
+		for {
+			if err != nil && ctx.Err() == nil {
+				log.Println("Warning: unable to sync movie embeddings:", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err = x.Sync(ctx)
+			case <-x.notify:
+				err = x.syncChanged(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the background sync and waits for a running sync to finish
+func (x *Index) Stop() {
+	if x.cancel != nil {
+		x.cancel()
+	}
+
+	x.wg.Wait()
+}
+
+// Notify asks for the movies with the given imdb ids to be re-embedded, or
+// removed when they were deleted. Without ids a full sync is scheduled.
+func (x *Index) Notify(imdbIds ...string) {
+	x.changedMu.Lock()
+
+	if len(imdbIds) == 0 {
+		x.changedAll = true
+	}
+
+	for _, imdbId := range imdbIds {
+		x.changed[imdbId] = true
+	}
+
+	x.changedMu.Unlock()
+
+	select {
+	case x.notify <- struct{}{}:
+	default:
+	}
+}
+
+// syncChanged syncs the movies passed to Notify since the last call
+func (x *Index) syncChanged(ctx context.Context) error {
+	x.changedMu.Lock()
+	all := x.changedAll
+	imdbIds := make([]string, 0, len(x.changed))
+
+	for imdbId := range x.changed {
+		imdbIds = append(imdbIds, imdbId)
+	}
+
+	x.changed = map[string]bool{}
+	x.changedAll = false
+	x.changedMu.Unlock()
+
+	if all {
+		return x.Sync(ctx)
+	}
+
+	return x.SyncMovies(ctx, imdbIds)
+}
+
+// SyncMovies embeds the given movies when their text changed and removes the
+// embeddings of those that no longer exist
+func (x *Index) SyncMovies(ctx context.Context, imdbIds []string) error {
+	if len(imdbIds) == 0 {
+		return nil
+	}
+
+	movies, err := x.movies.List(ctx, repository.MovieQuery{ImdbIDs: imdbIds})
+
+	if err != nil {
+		return err
+	}
+
+	found := make(map[string]bool, len(movies))
+	var stale []models.Movie
+
+	for _, movie := range movies {
+		found[movie.ImdbID] = true
+
+		embedding, err := x.embeddings.Get(ctx, movie.ImdbID, x.embedder.Model())
+
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+
+		if err != nil || embedding.TextHash != textHash(MovieText(movie)) {
+			stale = append(stale, movie)
+		}
+	}
+
+	if err := x.embedInBatches(ctx, stale); err != nil {
+		return err
+	}
+
+	var deleted []string
+
+	for _, imdbId := range imdbIds {
+		if !found[imdbId] {
+			deleted = append(deleted, imdbId)
+		}
+	}
+
+	return x.deleteEmbeddings(ctx, deleted)
+}
+
+// Sync pages through every movie, embedding those whose text changed or that
+// have no embedding for the current model, and removes the embeddings of
+// deleted movies and of other models.
+func (x *Index) Sync(ctx context.Context) error {
+	// only the hash of each embedding is loaded, not its vector
+	hashes, err := x.embeddings.Hashes(ctx, x.embedder.Model())
+
+	if err != nil {
+		return err
+	}
+
+	query := repository.MovieQuery{SortField: repository.SortByTitle, Limit: syncPageSize}
+
+	for {
+		movies, err := x.movies.List(ctx, query)
+
+		if err != nil {
+			return err
+		}
+
+		var stale []models.Movie
+
+		for _, movie := range movies {
+			hash := textHash(MovieText(movie))
+			stored, ok := hashes[movie.ImdbID]
+
+			// what is left in hashes after the last page belongs to deleted movies
+			delete(hashes, movie.ImdbID)
+
+			if !ok || stored != hash {
+				stale = append(stale, movie)
+				continue
+			}
+
+			if err := x.refreshVector(ctx, movie.ImdbID, hash); err != nil {
+				return err
+			}
+		}
+
+		if err := x.embedInBatches(ctx, stale); err != nil {
+			return err
+		}
+
+		if len(movies) < syncPageSize {
+			break
+		}
+
+		last := movies[len(movies)-1]
+		query.After = &repository.MovieCursor{Title: last.Title, ID: last.ID}
+	}
+
+	orphans := make([]string, 0, len(hashes))
+
+	for imdbId := range hashes {
+		orphans = append(orphans, imdbId)
+	}
+
+	if err := x.deleteEmbeddings(ctx, orphans); err != nil {
+		return err
+	}
+
+	// drop embeddings of models no longer in use
+	return x.embeddings.DeleteOtherModels(ctx, x.embedder.Model())
+}
+
+// embedInBatches embeds movies batchSize at a time
+func (x *Index) embedInBatches(ctx context.Context, movies []models.Movie) error {
+	for start := 0; start < len(movies); start += batchSize {
+		end := min(start+batchSize, len(movies))
+
+		if _, err := x.embedMovies(ctx, movies[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteEmbeddings removes the embeddings of the given movies deleteBatchSize at a time
+func (x *Index) deleteEmbeddings(ctx context.Context, imdbIds []string) error {
+	for start := 0; start < len(imdbIds); start += deleteBatchSize {
+		end := min(start+deleteBatchSize, len(imdbIds))
+
+		if err := x.embeddings.DeleteMovies(ctx, imdbIds[start:end]); err != nil {
+			return err
+		}
+	}
+
+	x.vectorsMu.Lock()
+	defer x.vectorsMu.Unlock()
+
+	if x.vectors != nil {
+		for _, imdbId := range imdbIds {
+			delete(x.vectors, imdbId)
+		}
+	}
+
+	return nil
+}
+
+// cacheVectors adds stored embeddings to the vector cache once it is loaded
+func (x *Index) cacheVectors(embeddings []models.MovieEmbedding) {
+	x.vectorsMu.Lock()
+	defer x.vectorsMu.Unlock()
+
+	if x.vectors == nil {
+		return
+	}
+
+	for _, embedding := range embeddings {
+		x.vectors[embedding.ImdbID] = cachedVector{textHash: embedding.TextHash, vector: embedding.Vector}
+	}
+}
+
+// refreshVector reloads the cached vector of a movie that was embedded
+// elsewhere, e.g. by another server, since the cache was loaded
+func (x *Index) refreshVector(ctx context.Context, imdbId, hash string) error {
+	x.vectorsMu.RLock()
+	cached, ok := x.vectors[imdbId]
+	loaded := x.vectors != nil
+	x.vectorsMu.RUnlock()
+
+	if !loaded || (ok && cached.textHash == hash) {
+		return nil
+	}
+
+	embedding, err := x.embeddings.Get(ctx, imdbId, x.embedder.Model())
+
+	if err != nil {
+		return err
+	}
+
+	x.cacheVectors([]models.MovieEmbedding{embedding})
+
+	return nil
+}
+
+// loadVectors fills the vector cache from the stored embeddings unless it is already loaded
+func (x *Index) loadVectors(ctx context.Context) error {
+	x.vectorsMu.RLock()
+	loaded := x.vectors != nil
+	x.vectorsMu.RUnlock()
+
+	if loaded {
+		return nil
+	}
+
+	embeddings, err := x.embeddings.ListByModel(ctx, x.embedder.Model())
+
+	if err != nil {
+		return err
+	}
+
+	vectors := make(map[string]cachedVector, len(embeddings))
+
+	for _, embedding := range embeddings {
+		vectors[embedding.ImdbID] = cachedVector{textHash: embedding.TextHash, vector: embedding.Vector}
+	}
+
+	x.vectorsMu.Lock()
+	defer x.vectorsMu.Unlock()
+
+	// embeddings written while loading are already in the store; keep the
+	// cache another request may have filled meanwhile
+	if x.vectors == nil {
+		x.vectors = vectors
+	}
+
+	return nil
+}
+
+// embedMovies computes, stores and returns the embeddings of movies
+func (x *Index) embedMovies(ctx context.Context, movies []models.Movie) ([][]float32, error) {
+	texts := make([]string, 0, len(movies))
+
+	for _, movie := range movies {
+		texts = append(texts, MovieText(movie))
+	}
+
+	vectors, err := x.embedder.Embed(ctx, texts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vectors) != len(movies) {
+		return nil, errors.New("embedder returned a different number of vectors than texts")
+	}
+
+	now := time.Now()
+	embeddings := make([]models.MovieEmbedding, 0, len(movies))
+
+	for i, movie := range movies {
+		embeddings = append(embeddings, models.MovieEmbedding{
+			ImdbID:    movie.ImdbID,
+			Model:     x.embedder.Model(),
+			TextHash:  textHash(texts[i]),
+			Vector:    vectors[i],
+			UpdatedAt: now,
+		})
+	}
+
+	if err := x.embeddings.Save(ctx, embeddings); err != nil {
+		return nil, err
+	}
+
+	x.cacheVectors(embeddings)
+
+	return vectors, nil
+}
+
+// movieVector returns the current embedding of a movie, embedding it first if it is missing or stale
+func (x *Index) movieVector(ctx context.Context, imdbId string) ([]float32, error) {
+	movie, err := x.movies.Get(ctx, imdbId)
+
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrMovieNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	embedding, err := x.embeddings.Get(ctx, imdbId, x.embedder.Model())
+
+	if err == nil && embedding.TextHash == textHash(MovieText(movie)) {
+		return embedding.Vector, nil
+	}
+
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	vectors, err := x.embedMovies(ctx, []models.Movie{movie})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return vectors[0], nil
+}
+
+// Similar returns up to limit movies nearest to imdbId by cosine similarity, best first
+func (x *Index) Similar(ctx context.Context, imdbId string, limit int) ([]models.SimilarMovie, error) {
+	vector, err := x.movieVector(ctx, imdbId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := x.loadVectors(ctx); err != nil {
+		return nil, err
+	}
+
+	var neighbours []models.Neighbour
+
+	x.vectorsMu.RLock()
+
+	for id, cached := range x.vectors {
+		if id != imdbId {
+			neighbours = append(neighbours, models.Neighbour{ImdbID: id, Score: Cosine(vector, cached.vector)})
+		}
+	}
+
+	x.vectorsMu.RUnlock()
+
+	sort.Slice(neighbours, func(i, j int) bool { return neighbours[i].Score > neighbours[j].Score })
+
+	if len(neighbours) > limit {
+		neighbours = neighbours[:limit]
+	}
+
+	ids := make([]string, 0, len(neighbours))
+
+	for _, neighbour := range neighbours {
+		ids = append(ids, neighbour.ImdbID)
+	}
+
+	movies, err := x.movies.List(ctx, repository.MovieQuery{ImdbIDs: ids})
+
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[string]models.Movie, len(movies))
+
+	for _, movie := range movies {
+		byId[movie.ImdbID] = movie
+	}
+
+	similar := make([]models.SimilarMovie, 0, len(neighbours))
+
+	for _, neighbour := range neighbours {
+		// skip movies deleted since the last sync
+		if movie, ok := byId[neighbour.ImdbID]; ok {
+			similar = append(similar, models.SimilarMovie{Movie: movie, Score: neighbour.Score})
+		}
+	}
+
+	return similar, nil
+}
+
+// textHash fingerprints the embedded text of a movie
+func textHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
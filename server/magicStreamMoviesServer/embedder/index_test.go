@@ -0,0 +1,83 @@
+package embedder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+)
+
+func TestIndexSyncMoviesUpdatesStoreAndCache(t *testing.T) {
+	ctx := context.Background()
+	movies := repository.NewMemoryMovieRepository()
+	embeddings := repository.NewMemoryEmbeddingRepository()
+
+	for _, movie := range []models.Movie{
+		{ImdbID: "tt1", Title: "Heat", AdminReview: "A tense crime thriller about a bank heist crew"},
+		{ImdbID: "tt2", Title: "Thief", AdminReview: "A crime thriller about a safecracker planning one last heist"},
+		{ImdbID: "tt3", Title: "Babe", AdminReview: "A gentle family film about a talking pig on a farm"},
+	} {
+		if err := movies.Insert(ctx, &movie); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	index := NewIndex(NewLocalEmbedder(DefaultLocalDimensions), movies, embeddings, 0)
+
+	if err := index.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// loads the vector cache
+	if _, err := index.Similar(ctx, "tt1", 10); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := movies.SetAdminReview(ctx, "tt2", "A gentle family film about a sheepdog pig on a farm", "job-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := movies.Delete(ctx, "tt3"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := index.SyncMovies(ctx, []string{"tt2", "tt3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := embeddings.Get(ctx, "tt3", index.embedder.Model()); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("embedding of deleted tt3: err = %v, want ErrNotFound", err)
+	}
+
+	stored, err := embeddings.Get(ctx, "tt2", index.embedder.Model())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := movies.Get(ctx, "tt2")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.TextHash != textHash(MovieText(updated)) {
+		t.Fatal("tt2 was not re-embedded after its review changed")
+	}
+
+	similar, err := index.Similar(ctx, "tt1", 10)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(similar) != 1 || similar[0].Movie.ImdbID != "tt2" {
+		t.Fatalf("similar = %+v, want only tt2", similar)
+	}
+
+	if index.vectors["tt2"].textHash != stored.TextHash {
+		t.Fatal("cached vector of tt2 was not replaced")
+	}
+}
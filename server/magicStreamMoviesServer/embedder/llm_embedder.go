@@ -0,0 +1,81 @@
+package embedder
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms/ollama"
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+// DefaultOpenAIEmbeddingModel is used when OPENAI_EMBEDDING_MODEL is not set
+const DefaultOpenAIEmbeddingModel = "text-embedding-3-small"
+
+// LLMEmbedder embeds texts with a hosted or local embedding model through langchaingo
+type LLMEmbedder struct {
+	embedder *embeddings.EmbedderImpl
+	model    string
+}
+
+// NewLLMEmbedder creates an LLMEmbedder from any langchaingo embedding client;
+// model names the vector space the client produces.
+func NewLLMEmbedder(client embeddings.EmbedderClient, model string) (*LLMEmbedder, error) {
+	embedder, err := embeddings.NewEmbedder(client)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &LLMEmbedder{embedder: embedder, model: model}, nil
+}
+
+// NewOpenAIEmbedder creates an LLMEmbedder backed by OpenAI
+func NewOpenAIEmbedder(apiKey, model string) (*LLMEmbedder, error) {
+	if apiKey == "" {
+		return nil, errors.New("could not read OPENAI_API_KEY")
+	}
+
+	if model == "" {
+		model = DefaultOpenAIEmbeddingModel
+	}
+
+	llm, err := openai.New(openai.WithToken(apiKey), openai.WithEmbeddingModel(model))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLLMEmbedder(llm, "openai-"+model)
+}
+
+// NewOllamaEmbedder creates an LLMEmbedder backed by an Ollama-compatible HTTP endpoint
+func NewOllamaEmbedder(serverURL, model string) (*LLMEmbedder, error) {
+	if model == "" {
+		return nil, errors.New("could not read OLLAMA_EMBEDDING_MODEL")
+	}
+
+	opts := []ollama.Option{ollama.WithModel(model)}
+
+	if serverURL != "" {
+		opts = append(opts, ollama.WithServerURL(serverURL))
+	}
+
+	llm, err := ollama.New(opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLLMEmbedder(llm, "ollama-"+model)
+}
+
+// Model returns the provider and model the vectors come from
+func (e *LLMEmbedder) Model() string {
+	return e.model
+}
+
+// Embed sends texts to the embedding model in batches
+func (e *LLMEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return e.embedder.EmbedDocuments(ctx, texts)
+}
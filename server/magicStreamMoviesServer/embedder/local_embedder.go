@@ -0,0 +1,116 @@
+package embedder
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultLocalDimensions is the vector size of the local embedder
+const DefaultLocalDimensions = 512
+
+// stopWords carry no meaning for similarity and are skipped
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "for": true, "from": true, "genres": true,
+	"has": true, "in": true, "is": true, "it": true, "its": true, "of": true,
+	"on": true, "or": true, "review": true, "that": true, "the": true, "this": true,
+	"title": true, "to": true, "was": true, "with": true,
+}
+
+// LocalEmbedder is an offline embedder that hashes words and word pairs into
+// a fixed number of dimensions. It needs no model or network access, which
+// makes it suitable for development and as a fallback.
+type LocalEmbedder struct {
+	Dimensions int
+}
+
+// NewLocalEmbedder creates a LocalEmbedder producing vectors of the given size
+func NewLocalEmbedder(dimensions int) *LocalEmbedder {
+	if dimensions < 1 {
+		dimensions = DefaultLocalDimensions
+	}
+
+	return &LocalEmbedder{Dimensions: dimensions}
+}
+
+// Model names the hashing scheme and dimension count
+func (e *LocalEmbedder) Model() string {
+	return "local-hash-" + strconv.Itoa(e.Dimensions)
+}
+
+// Embed hashes each text into a unit-length vector
+func (e *LocalEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+
+	for _, text := range texts {
+		vectors = append(vectors, e.embed(text))
+	}
+
+	return vectors, nil
+}
+
+// embed builds a log-scaled term frequency vector of the text's words and
+// adjacent word pairs, using the hash sign to reduce collisions, normalised to unit length.
+func (e *LocalEmbedder) embed(text string) []float32 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	counts := map[string]float64{}
+	previous := ""
+
+	for _, word := range words {
+		if stopWords[word] {
+			previous = ""
+			continue
+		}
+
+		counts[word]++
+
+		if previous != "" {
+			// pairs count half so single words stay the main signal
+			counts[previous+" "+word] += 0.5
+		}
+
+		previous = word
+	}
+
+	vector := make([]float64, e.Dimensions)
+
+	for feature, count := range counts {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+
+		sign := 1.0
+		if sum&(1<<63) != 0 {
+			sign = -1
+		}
+
+		vector[sum%uint64(e.Dimensions)] += sign * (1 + math.Log(count))
+	}
+
+	var norm float64
+
+	for _, value := range vector {
+		norm += value * value
+	}
+
+	result := make([]float32, e.Dimensions)
+
+	if norm == 0 {
+		return result
+	}
+
+	norm = math.Sqrt(norm)
+
+	for i, value := range vector {
+		result[i] = float32(value / norm)
+	}
+
+	return result
+}
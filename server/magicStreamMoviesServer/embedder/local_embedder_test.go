@@ -0,0 +1,54 @@
+package embedder
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLocalEmbedderUnitLength(t *testing.T) {
+	e := NewLocalEmbedder(64)
+
+	vector := e.embed("Title: Heat\nGenres: Crime, Thriller\nReview: A tense heist epic")
+
+	if len(vector) != 64 {
+		t.Fatalf("vector has %d dimensions, want 64", len(vector))
+	}
+
+	var norm float64
+
+	for _, value := range vector {
+		norm += float64(value) * float64(value)
+	}
+
+	if math.Abs(math.Sqrt(norm)-1) > 1e-5 {
+		t.Fatalf("vector length = %f, want 1", math.Sqrt(norm))
+	}
+}
+
+func TestLocalEmbedderSimilarTextsScoreHigher(t *testing.T) {
+	e := NewLocalEmbedder(DefaultLocalDimensions)
+
+	heist := e.embed("A tense crime thriller about a bank heist crew")
+	otherHeist := e.embed("A crime thriller following a heist crew planning one last bank job")
+	cartoon := e.embed("A gentle animated musical about talking farm animals")
+
+	related := Cosine(heist, otherHeist)
+	unrelated := Cosine(heist, cartoon)
+
+	if related <= unrelated {
+		t.Fatalf("related score %f is not above unrelated score %f", related, unrelated)
+	}
+}
+
+func TestLocalEmbedderEmptyText(t *testing.T) {
+	e := NewLocalEmbedder(16)
+
+	// stop words only carry no features either
+	for _, text := range []string{"", "the and of"} {
+		for i, value := range e.embed(text) {
+			if value != 0 {
+				t.Fatalf("embed(%q)[%d] = %f, want a zero vector", text, i, value)
+			}
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// leaseMargin is added to the ranking call timeout to bound how long a running
+// job is held: a worker always finishes within it, so a job still running after
+// its lease belongs to a server that stopped and may be claimed again.
+const leaseMargin = time.Minute
+
+// RankFunc classifies a review into a ranking
+type RankFunc func(ctx context.Context, review string) (models.Ranking, error)
+
+// RankingQueue classifies admin reviews in the background with a pool of workers.
+// Jobs are stored through a RankingJobRepository so their status can be polled
+// and so jobs that were pending when the server stopped are picked up again.
+type RankingQueue struct {
+	rank          RankFunc
+	jobs          repository.RankingJobRepository
+	movies        repository.MovieRepository
+	workers       int
+	maxAttempts   int
+	baseBackoff   time.Duration
+	callTimeout   time.Duration
+	dbTimeout     time.Duration
+	sweepInterval time.Duration
+
+	// permanent reports errors that retrying cannot fix
+	permanent func(error) bool
+
+	queue  chan bson.ObjectID
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+}
+
+// NewRankingQueue creates a queue that ranks reviews with rank using the given
+// number of workers, trying each job up to maxAttempts times with exponential
+// backoff. Errors for which permanent returns true fail the job immediately.
+// Outcomes are stored on the job in jobs and on the movie in movies.
+func NewRankingQueue(rank RankFunc, jobs repository.RankingJobRepository, movies repository.MovieRepository, workers, maxAttempts int, permanent func(error) bool) *RankingQueue {
+	if workers < 1 {
+		workers = 1
+	}
+
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	if permanent == nil {
+		permanent = func(error) bool { return false }
+	}
+
+	return &RankingQueue{
+		rank:          rank,
+		jobs:          jobs,
+		movies:        movies,
+		workers:       workers,
+		maxAttempts:   maxAttempts,
+		baseBackoff:   2 * time.Second,
+		callTimeout:   100 * time.Second,
+		dbTimeout:     30 * time.Second,
+		sweepInterval: 10 * time.Second,
+		permanent:     permanent,
+		queue:         make(chan bson.ObjectID, 256),
+	}
+}
+
+// Start launches the workers plus a sweeper that re-queues pending jobs whose
+// retry time has come, including jobs left over from a previous run, and
+// releases running jobs whose lease has expired.
+func (q *RankingQueue) Start(ctx context.Context) {
+	ctx, q.cancel = context.WithCancel(ctx)
+
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work(ctx)
+	}
+
+	q.wg.Add(1)
+	go q.sweep(ctx)
+}
+
+// Stop stops the workers and waits for the jobs in progress to finish.
+// Jobs that did not start stay pending and are resumed on the next Start.
+func (q *RankingQueue) Stop() {
+	if q.cancel != nil {
+		q.cancel()
+	}
+
+	q.wg.Wait()
+}
+
+// Enqueue stores a new pending job with the given id for the movie's review and schedules it
+func (q *RankingQueue) Enqueue(ctx context.Context, id bson.ObjectID, imdbId, review string) (models.RankingJob, error) {
+	now := time.Now()
+
+	job := models.RankingJob{
+		ID:            id,
+		ImdbID:        imdbId,
+		AdminReview:   review,
+		Status:        models.JobStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := q.jobs.Insert(ctx, job); err != nil {
+		return models.RankingJob{}, err
+	}
+
+	q.schedule(job.ID)
+
+	return job, nil
+}
+
+// schedule hands a job to the workers without blocking; if the queue is full
+// the job stays pending and the sweeper picks it up later.
+func (q *RankingQueue) schedule(id bson.ObjectID) {
+	select {
+	case q.queue <- id:
+	default:
+	}
+}
+
+func (q *RankingQueue) work(ctx context.Context) {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-q.queue:
+			q.process(ctx, id)
+		}
+	}
+}
+
+// sweep periodically releases expired leases and re-queues pending jobs that are due
+func (q *RankingQueue) sweep(ctx context.Context) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		q.releaseExpired(ctx)
+		q.enqueueDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// releaseExpired makes jobs held by a stopped server pending again. Jobs
+// running on live workers keep their lease, so other replicas are unaffected.
+func (q *RankingQueue) releaseExpired(ctx context.Context) {
+	releaseCtx, cancel := context.WithTimeout(ctx, q.dbTimeout)
+	defer cancel()
+
+	released, err := q.jobs.ReleaseExpired(releaseCtx, time.Now())
+
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Println("Warning: unable to release expired ranking jobs:", err)
+		}
+		return
+	}
+
+	if released > 0 {
+		log.Printf("Released %d ranking jobs whose worker stopped", released)
+	}
+}
+
+func (q *RankingQueue) enqueueDue(ctx context.Context) {
+	findCtx, cancel := context.WithTimeout(ctx, q.dbTimeout)
+	defer cancel()
+
+	due, err := q.jobs.Due(findCtx, time.Now(), int64(cap(q.queue)))
+
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Println("Warning: unable to load pending ranking jobs:", err)
+		}
+		return
+	}
+
+	for _, id := range due {
+		q.schedule(id)
+	}
+}
+
+// dbContext bounds a single storage call made by a worker
+func (q *RankingQueue) dbContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), q.dbTimeout)
+}
+
+// process claims a pending job, ranks the review and records the outcome on
+// both the job and the movie.
+func (q *RankingQueue) process(ctx context.Context, id bson.ObjectID) {
+	now := time.Now()
+
+	claimCtx, cancel := q.dbContext()
+	job, err := q.jobs.Claim(claimCtx, id, now, now.Add(q.callTimeout+leaseMargin))
+	cancel()
+
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			log.Println("Warning: unable to claim ranking job:", err)
+		}
+		return
+	}
+
+	rankCtx, rankCancel := context.WithTimeout(ctx, q.callTimeout)
+	ranking, err := q.rank(rankCtx, job.AdminReview)
+	rankCancel()
+
+	// The writes below each get a fresh context: the ranking call may have
+	// taken longer than any deadline set before it.
+	if err == nil {
+		q.succeed(job, ranking)
+		return
+	}
+
+	if ctx.Err() != nil {
+		// shutting down: give the attempt back so the job runs on the next start
+		attempts := job.Attempts - 1
+		q.finish(job, repository.JobOutcome{Status: models.JobStatusPending, Attempts: &attempts})
+		return
+	}
+
+	if q.permanent(err) || job.Attempts >= q.maxAttempts {
+		q.fail(job, err)
+		return
+	}
+
+	backoff := q.baseBackoff << (job.Attempts - 1)
+	lastError := err.Error()
+	nextAttemptAt := time.Now().Add(backoff)
+
+	if q.finish(job, repository.JobOutcome{
+		Status:        models.JobStatusPending,
+		LastError:     &lastError,
+		NextAttemptAt: &nextAttemptAt,
+	}) {
+		time.AfterFunc(backoff, func() { q.schedule(job.ID) })
+	}
+}
+
+func (q *RankingQueue) succeed(job models.RankingJob, ranking models.Ranking) {
+	lastError := ""
+
+	if !q.finish(job, repository.JobOutcome{
+		Status:    models.JobStatusSucceeded,
+		Ranking:   &ranking,
+		LastError: &lastError,
+	}) {
+		return
+	}
+
+	ctx, cancel := q.dbContext()
+	defer cancel()
+
+	// Only touch the movie if this job is still the latest one for its review
+	if err := q.movies.SetRankingResult(ctx, job.ImdbID, job.ID.Hex(), models.JobStatusSucceeded, &ranking); err != nil {
+		log.Println("Warning: unable to store ranking for", job.ImdbID, err)
+	}
+}
+
+func (q *RankingQueue) fail(job models.RankingJob, cause error) {
+	lastError := cause.Error()
+
+	if !q.finish(job, repository.JobOutcome{Status: models.JobStatusFailed, LastError: &lastError}) {
+		return
+	}
+
+	ctx, cancel := q.dbContext()
+	defer cancel()
+
+	if err := q.movies.SetRankingResult(ctx, job.ImdbID, job.ID.Hex(), models.JobStatusFailed, nil); err != nil {
+		log.Println("Warning: unable to mark ranking failed for", job.ImdbID, err)
+	}
+}
+
+// finish records outcome on the claimed job and reports whether the claim was
+// still held; a job whose lease expired may already be running elsewhere.
+func (q *RankingQueue) finish(job models.RankingJob, outcome repository.JobOutcome) bool {
+	ctx, cancel := q.dbContext()
+	defer cancel()
+
+	err := q.jobs.Finish(ctx, job.ID, job.Attempts, outcome)
+
+	if errors.Is(err, repository.ErrNotFound) {
+		log.Println("Warning: ranking job", job.ID.Hex(), "was reclaimed before it finished")
+		return false
+	}
+
+	if err != nil {
+		log.Println("Warning: unable to update ranking job", job.ID.Hex(), err)
+		return false
+	}
+
+	return true
+}
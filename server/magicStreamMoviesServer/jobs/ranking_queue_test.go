@@ -0,0 +1,271 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/ranker"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var testRankings = []models.Ranking{
+	{RankingValue: 1, RankingName: "Excellent"},
+	{RankingValue: 2, RankingName: "Good"},
+	{RankingValue: 3, RankingName: "Bad"},
+}
+
+// newTestQueue creates a queue ranking with fake over in-memory repositories,
+// with short delays so retries and sweeps happen within a test.
+func newTestQueue(t *testing.T, fake *ranker.FakeRanker, maxAttempts int) (*RankingQueue, *repository.Repositories) {
+	t.Helper()
+
+	repos := repository.NewMemoryRepositories()
+
+	rank := func(ctx context.Context, review string) (models.Ranking, error) {
+		return fake.RankReview(ctx, review, testRankings)
+	}
+
+	permanent := func(err error) bool { return errors.Is(err, ranker.ErrNoValidRanking) }
+
+	q := NewRankingQueue(rank, repos.Jobs, repos.Movies, 2, maxAttempts, permanent)
+	q.baseBackoff = 10 * time.Millisecond
+	q.sweepInterval = 20 * time.Millisecond
+
+	return q, repos
+}
+
+// addReviewedMovie stores a movie whose admin review is pending in the job with jobId
+func addReviewedMovie(t *testing.T, movies repository.MovieRepository, imdbId string, jobId bson.ObjectID) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	if err := movies.Insert(ctx, &models.Movie{ImdbID: imdbId, Title: imdbId}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := movies.SetAdminReview(ctx, imdbId, "review of "+imdbId, jobId.Hex()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitForJob polls the job until it reaches status or the test times out
+func waitForJob(t *testing.T, jobs repository.RankingJobRepository, id bson.ObjectID, status string) models.RankingJob {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for {
+		job, err := jobs.Get(context.Background(), id)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if job.Status == status {
+			return job
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s is %s, want %s", id.Hex(), job.Status, status)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRankingQueueRanksReview(t *testing.T) {
+	fake := &ranker.FakeRanker{Response: "Good"}
+	q, repos := newTestQueue(t, fake, 3)
+
+	q.Start(context.Background())
+	defer q.Stop()
+
+	jobId := bson.NewObjectID()
+	addReviewedMovie(t, repos.Movies, "tt0001", jobId)
+
+	if _, err := q.Enqueue(context.Background(), jobId, "tt0001", "review of tt0001"); err != nil {
+		t.Fatal(err)
+	}
+
+	job := waitForJob(t, repos.Jobs, jobId, models.JobStatusSucceeded)
+
+	if job.Ranking == nil || job.Ranking.RankingName != "Good" {
+		t.Errorf("job ranking = %v, want Good", job.Ranking)
+	}
+
+	if job.LeaseExpiresAt != nil {
+		t.Errorf("finished job still holds a lease until %v", job.LeaseExpiresAt)
+	}
+
+	movie, err := repos.Movies.Get(context.Background(), "tt0001")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if movie.Ranking.RankingName != "Good" || movie.RankingStatus != models.JobStatusSucceeded {
+		t.Errorf("movie ranking = %v (%s), want Good (succeeded)", movie.Ranking, movie.RankingStatus)
+	}
+
+	if reviews := fake.Reviews(); len(reviews) != 1 || reviews[0] != "review of tt0001" {
+		t.Errorf("ranked reviews = %v, want [review of tt0001]", reviews)
+	}
+}
+
+func TestRankingQueueFailsOnPermanentError(t *testing.T) {
+	fake := &ranker.FakeRanker{Response: "no idea"}
+	q, repos := newTestQueue(t, fake, 3)
+
+	q.Start(context.Background())
+	defer q.Stop()
+
+	jobId := bson.NewObjectID()
+	addReviewedMovie(t, repos.Movies, "tt0002", jobId)
+
+	if _, err := q.Enqueue(context.Background(), jobId, "tt0002", "review of tt0002"); err != nil {
+		t.Fatal(err)
+	}
+
+	job := waitForJob(t, repos.Jobs, jobId, models.JobStatusFailed)
+
+	if job.Attempts != 1 {
+		t.Errorf("attempts = %d, want 1 for a permanent error", job.Attempts)
+	}
+
+	movie, _ := repos.Movies.Get(context.Background(), "tt0002")
+
+	if movie.RankingStatus != models.JobStatusFailed {
+		t.Errorf("movie ranking status = %s, want failed", movie.RankingStatus)
+	}
+}
+
+func TestRankingQueueRetriesUntilMaxAttempts(t *testing.T) {
+	fake := &ranker.FakeRanker{Err: errors.New("model unavailable")}
+	q, repos := newTestQueue(t, fake, 3)
+
+	q.Start(context.Background())
+	defer q.Stop()
+
+	jobId := bson.NewObjectID()
+	addReviewedMovie(t, repos.Movies, "tt0003", jobId)
+
+	if _, err := q.Enqueue(context.Background(), jobId, "tt0003", "review of tt0003"); err != nil {
+		t.Fatal(err)
+	}
+
+	job := waitForJob(t, repos.Jobs, jobId, models.JobStatusFailed)
+
+	if job.Attempts != 3 || len(fake.Reviews()) != 3 {
+		t.Errorf("attempts = %d with %d ranking calls, want 3", job.Attempts, len(fake.Reviews()))
+	}
+
+	if job.LastError != "model unavailable" {
+		t.Errorf("last error = %q, want model unavailable", job.LastError)
+	}
+}
+
+func TestRankingQueueLeavesNewerReviewAlone(t *testing.T) {
+	fake := &ranker.FakeRanker{Response: "Bad"}
+	q, repos := newTestQueue(t, fake, 3)
+
+	q.Start(context.Background())
+	defer q.Stop()
+
+	// the movie's review was replaced by a newer job after this one was queued
+	jobId := bson.NewObjectID()
+	addReviewedMovie(t, repos.Movies, "tt0004", bson.NewObjectID())
+
+	if _, err := q.Enqueue(context.Background(), jobId, "tt0004", "older review"); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForJob(t, repos.Jobs, jobId, models.JobStatusSucceeded)
+
+	movie, _ := repos.Movies.Get(context.Background(), "tt0004")
+
+	if movie.RankingStatus != models.JobStatusPending || movie.Ranking.RankingName != "" {
+		t.Errorf("movie ranking = %v (%s), want it untouched", movie.Ranking, movie.RankingStatus)
+	}
+}
+
+func TestRankingQueueReclaimsOnlyExpiredLeases(t *testing.T) {
+	fake := &ranker.FakeRanker{Response: "Excellent"}
+	q, repos := newTestQueue(t, fake, 3)
+
+	ctx := context.Background()
+	now := time.Now()
+	expired := now.Add(-time.Second)
+	held := now.Add(time.Hour)
+
+	// one job was left running by a server that stopped, the other is held by a live replica
+	orphan := models.RankingJob{ID: bson.NewObjectID(), ImdbID: "tt0005", AdminReview: "orphaned", Status: models.JobStatusRunning, Attempts: 1, LeaseExpiresAt: &expired}
+	active := models.RankingJob{ID: bson.NewObjectID(), ImdbID: "tt0006", AdminReview: "in progress", Status: models.JobStatusRunning, Attempts: 1, LeaseExpiresAt: &held}
+
+	for _, job := range []models.RankingJob{orphan, active} {
+		addReviewedMovie(t, repos.Movies, job.ImdbID, job.ID)
+
+		if err := repos.Jobs.Insert(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	q.Start(ctx)
+	defer q.Stop()
+
+	job := waitForJob(t, repos.Jobs, orphan.ID, models.JobStatusSucceeded)
+
+	if job.Attempts != 2 {
+		t.Errorf("reclaimed job attempts = %d, want 2", job.Attempts)
+	}
+
+	// give the sweeper a few more rounds to (wrongly) reclaim the active job
+	time.Sleep(5 * q.sweepInterval)
+
+	if job, _ := repos.Jobs.Get(ctx, active.ID); job.Status != models.JobStatusRunning || job.Attempts != 1 {
+		t.Errorf("job with a live lease is %s after %d attempts, want it left running", job.Status, job.Attempts)
+	}
+}
+
+func TestRankingQueueStopGivesBackInterruptedAttempt(t *testing.T) {
+	started := make(chan struct{})
+	var once sync.Once
+
+	repos := repository.NewMemoryRepositories()
+
+	// the ranking call blocks until the queue is stopped
+	rank := func(ctx context.Context, review string) (models.Ranking, error) {
+		once.Do(func() { close(started) })
+		<-ctx.Done()
+		return models.Ranking{}, ctx.Err()
+	}
+
+	q := NewRankingQueue(rank, repos.Jobs, repos.Movies, 1, 3, nil)
+
+	q.Start(context.Background())
+
+	jobId := bson.NewObjectID()
+	addReviewedMovie(t, repos.Movies, "tt0007", jobId)
+
+	if _, err := q.Enqueue(context.Background(), jobId, "tt0007", "review of tt0007"); err != nil {
+		t.Fatal(err)
+	}
+
+	<-started
+	q.Stop()
+
+	job, err := repos.Jobs.Get(context.Background(), jobId)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if job.Status != models.JobStatusPending || job.Attempts != 0 || job.LeaseExpiresAt != nil {
+		t.Errorf("interrupted job is %s after %d attempts (lease %v), want pending after 0", job.Status, job.Attempts, job.LeaseExpiresAt)
+	}
+}
@@ -0,0 +1,318 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ErrRerankRunning is returned when a bulk re-rank is requested while another one is in progress
+var ErrRerankRunning = errors.New("a re-rank is already running")
+
+// errMovieChanged is recorded as a failure when a new ranking could not be
+// stored because the movie changed while it was being ranked
+var errMovieChanged = errors.New("movie was deleted, renamed, re-reviewed or queued for ranking during the re-rank")
+
+// DefaultRerankConcurrency is the number of reviews ranked in parallel by default
+const DefaultRerankConcurrency = 4
+
+// progressEvery is how many movies are processed between progress saves
+const progressEvery = 10
+
+// rerankPageSize is how many movies are loaded at a time during a run
+const rerankPageSize = 100
+
+// Reranker re-classifies every movie's admin review against the current rankings
+type Reranker struct {
+	rank   RankFunc
+	movies repository.MovieRepository
+	runs   repository.RerankRunRepository
+
+	mu      sync.Mutex
+	running bool
+	wg      sync.WaitGroup
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// NewReranker creates a Reranker that classifies the reviews of movies with
+// rank. Runs started with Start are stored in runs.
+func NewReranker(rank RankFunc, movies repository.MovieRepository, runs repository.RerankRunRepository) *Reranker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Reranker{rank: rank, movies: movies, runs: runs, ctx: ctx, cancel: cancel}
+}
+
+// Start stores a new run and executes it in the background. Only one run may
+// be active at a time; progress can be followed through the stored run.
+func (r *Reranker) Start(dryRun bool, concurrency int) (models.RerankRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running {
+		return models.RerankRun{}, ErrRerankRunning
+	}
+
+	run := NewRerankRun(dryRun, concurrency)
+
+	ctx, cancel := context.WithTimeout(r.ctx, 30*time.Second)
+	defer cancel()
+
+	if err := r.runs.Insert(ctx, run); err != nil {
+		return models.RerankRun{}, err
+	}
+
+	r.running = true
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+		defer func() {
+			r.mu.Lock()
+			r.running = false
+			r.mu.Unlock()
+		}()
+
+		r.run(r.ctx, &run, nil, true)
+	}()
+
+	return run, nil
+}
+
+// Stop cancels the active run, if any, and waits for it to record its state
+func (r *Reranker) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// NewRerankRun builds a run in the running state; Start saves it, while
+// synchronous callers can pass it straight to Run.
+func NewRerankRun(dryRun bool, concurrency int) models.RerankRun {
+	if concurrency < 1 {
+		concurrency = DefaultRerankConcurrency
+	}
+
+	return models.RerankRun{
+		ID:          bson.NewObjectID(),
+		Status:      models.JobStatusRunning,
+		DryRun:      dryRun,
+		Concurrency: concurrency,
+		Diffs:       []models.RankingDiff{},
+		Failures:    []models.RerankFailure{},
+		StartedAt:   time.Now(),
+	}
+}
+
+// Run classifies every movie that has an admin review and no ranking pending
+// in the queue, at most run.Concurrency at a time, and records on run which
+// rankings changed. Unless run.DryRun is set the new rankings are written to
+// the movies; a movie that changed meanwhile is recorded as a failure. progress, if not nil, is
+// called with a snapshot of the run's counts after every movie.
+func (r *Reranker) Run(ctx context.Context, run *models.RerankRun, progress func(models.RerankRun)) error {
+	return r.run(ctx, run, progress, false)
+}
+
+// run implements Run. With persist, used for runs stored by Start, the run
+// document is saved as it progresses and when it finishes, and every diff and
+// failure is stored in the run repository instead of being kept on run.
+func (r *Reranker) run(ctx context.Context, run *models.RerankRun, progress func(models.RerankRun), persist bool) error {
+	var mu sync.Mutex
+
+	snapshot := func() models.RerankRun {
+		copied := *run
+		copied.Diffs = nil
+		copied.Failures = nil
+		return copied
+	}
+
+	finish := func(err error) error {
+		mu.Lock()
+		now := time.Now()
+		run.FinishedAt = &now
+		run.Status = models.JobStatusSucceeded
+		if err != nil {
+			run.Status = models.JobStatusFailed
+			run.Error = err.Error()
+		}
+		final := snapshot()
+		mu.Unlock()
+
+		if persist {
+			r.saveRun(final)
+		}
+
+		if progress != nil {
+			progress(final)
+		}
+
+		return err
+	}
+
+	// Movies whose review is waiting in the ranking queue are left to the queue
+	query := repository.MovieQuery{WithAdminReview: true, ExcludeRankingStatus: models.JobStatusPending}
+
+	countCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	total, err := r.movies.Count(countCtx, query)
+	cancel()
+
+	if err != nil {
+		return finish(err)
+	}
+
+	run.Total = total
+
+	semaphore := make(chan struct{}, run.Concurrency)
+	var wg sync.WaitGroup
+
+	rankMovie := func(movie models.Movie) {
+		defer wg.Done()
+		defer func() { <-semaphore }()
+
+		ranking, rankErr := r.rank(ctx, movie.AdminReview)
+
+		var writeErr error
+
+		if rankErr == nil && ranking != movie.Ranking && !run.DryRun {
+			writeErr = r.applyRanking(movie, ranking)
+		}
+
+		var diff *models.RankingDiff
+		var failure *models.RerankFailure
+
+		switch {
+		case rankErr != nil:
+			failure = &models.RerankFailure{RunID: run.ID, ImdbID: movie.ImdbID, Error: rankErr.Error()}
+		case writeErr != nil:
+			failure = &models.RerankFailure{RunID: run.ID, ImdbID: movie.ImdbID, Error: writeErr.Error()}
+		case ranking != movie.Ranking:
+			diff = &models.RankingDiff{
+				RunID:  run.ID,
+				ImdbID: movie.ImdbID,
+				Title:  movie.Title,
+				Old:    movie.Ranking,
+				New:    ranking,
+			}
+		}
+
+		mu.Lock()
+		run.Processed++
+
+		if failure != nil {
+			run.Failed++
+			if !persist {
+				run.Failures = append(run.Failures, *failure)
+			}
+		}
+
+		if diff != nil {
+			run.Changed++
+			if !persist {
+				run.Diffs = append(run.Diffs, *diff)
+			}
+		}
+
+		current := snapshot()
+		mu.Unlock()
+
+		if persist {
+			if failure != nil {
+				r.saveEntry(func(ctx context.Context) error { return r.runs.AddFailure(ctx, *failure) })
+			}
+
+			if diff != nil {
+				r.saveEntry(func(ctx context.Context) error { return r.runs.AddDiff(ctx, *diff) })
+			}
+
+			if current.Processed%progressEvery == 0 {
+				r.saveRun(current)
+			}
+		}
+
+		if progress != nil {
+			progress(current)
+		}
+	}
+
+	// Page through the movies by title; re-ranking never changes a title, so
+	// the pages stay stable while rankings are written.
+	query.SortField = repository.SortByTitle
+	query.Limit = rerankPageSize
+
+	for ctx.Err() == nil {
+		pageCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		movies, err := r.movies.List(pageCtx, query)
+		cancel()
+
+		if err != nil {
+			wg.Wait()
+			return finish(err)
+		}
+
+		for _, movie := range movies {
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+			}
+
+			if ctx.Err() != nil {
+				break
+			}
+
+			wg.Add(1)
+			go rankMovie(movie)
+		}
+
+		if len(movies) < rerankPageSize {
+			break
+		}
+
+		last := movies[len(movies)-1]
+		query.After = &repository.MovieCursor{Title: last.Title, ID: last.ID}
+	}
+
+	wg.Wait()
+
+	return finish(ctx.Err())
+}
+
+// applyRanking stores a new ranking on the movie. It fails with
+// errMovieChanged when the movie is gone, its review changed meanwhile or a
+// queued ranking of it is pending, so the run does not overwrite it.
+func (r *Reranker) applyRanking(movie models.Movie, ranking models.Ranking) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := r.movies.SetRerankResult(ctx, movie.ImdbID, movie.AdminReview, ranking)
+
+	if errors.Is(err, repository.ErrNotFound) {
+		return errMovieChanged
+	}
+
+	return err
+}
+
+// saveRun updates the stored run
+func (r *Reranker) saveRun(run models.RerankRun) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := r.runs.Save(ctx, run); err != nil {
+		log.Println("Warning: unable to save re-rank progress:", err)
+	}
+}
+
+// saveEntry stores a diff or failure of a run with save
+func (r *Reranker) saveEntry(save func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := save(ctx); err != nil {
+		log.Println("Warning: unable to store re-rank result:", err)
+	}
+}
@@ -0,0 +1,185 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+)
+
+// rankByReview ranks a review "good" as Good and anything else as Excellent
+func rankByReview(ctx context.Context, review string) (models.Ranking, error) {
+	if review == "good" {
+		return testRankings[1], nil
+	}
+
+	return testRankings[0], nil
+}
+
+// addRankedMovie stores a movie with an admin review and a ranking
+func addRankedMovie(t *testing.T, movies repository.MovieRepository, imdbId, review string, ranking models.Ranking) {
+	t.Helper()
+
+	movie := &models.Movie{ImdbID: imdbId, Title: imdbId, AdminReview: review, Ranking: ranking}
+
+	if err := movies.Insert(context.Background(), movie); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRerankerRunPagesThroughReviewedMovies(t *testing.T) {
+	repos := repository.NewMemoryRepositories()
+
+	// more than one page, half of them ranked differently than the ranker would
+	for i := 0; i < rerankPageSize+10; i++ {
+		imdbId := "tt" + string(rune('a'+i/26)) + string(rune('a'+i%26))
+
+		if i%2 == 0 {
+			addRankedMovie(t, repos.Movies, imdbId, "good", testRankings[1])
+		} else {
+			addRankedMovie(t, repos.Movies, imdbId, "great", testRankings[2])
+		}
+	}
+
+	// movies without a review are not re-ranked
+	addRankedMovie(t, repos.Movies, "tt0", "", testRankings[2])
+
+	reranker := NewReranker(rankByReview, repos.Movies, repos.RerankRuns)
+	run := NewRerankRun(false, 4)
+
+	if err := reranker.Run(context.Background(), &run, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := int64(rerankPageSize + 10)
+
+	if run.Total != want || run.Processed != want || run.Changed != want/2 || run.Failed != 0 {
+		t.Fatalf("run = %+v, want %d processed and %d changed", run, want, want/2)
+	}
+
+	movie, err := repos.Movies.Get(context.Background(), "ttab")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if movie.Ranking != testRankings[0] {
+		t.Fatalf("ranking = %+v, want %+v", movie.Ranking, testRankings[0])
+	}
+
+	unreviewed, err := repos.Movies.Get(context.Background(), "tt0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if unreviewed.Ranking != testRankings[2] {
+		t.Fatalf("movie without a review was re-ranked to %+v", unreviewed.Ranking)
+	}
+}
+
+func TestRerankerStartStoresRun(t *testing.T) {
+	repos := repository.NewMemoryRepositories()
+	addRankedMovie(t, repos.Movies, "tt1", "great", testRankings[2])
+
+	reranker := NewReranker(rankByReview, repos.Movies, repos.RerankRuns)
+	defer reranker.Stop()
+
+	run, err := reranker.Start(true, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for {
+		stored, err := repos.RerankRuns.Get(context.Background(), run.ID)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if stored.FinishedAt != nil {
+			if stored.Status != models.JobStatusSucceeded || stored.Changed != 1 {
+				t.Fatalf("run = %+v, want one change", stored)
+			}
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("re-rank did not finish")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	diffs, err := repos.RerankRuns.Diffs(context.Background(), run.ID, 0, 10)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diffs) != 1 || diffs[0].ImdbID != "tt1" || diffs[0].New != testRankings[0] {
+		t.Fatalf("diffs = %+v, want tt1 moving to Excellent", diffs)
+	}
+
+	// a dry run does not write the ranking
+	movie, err := repos.Movies.Get(context.Background(), "tt1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if movie.Ranking != testRankings[2] {
+		t.Fatalf("dry run changed the ranking to %+v", movie.Ranking)
+	}
+}
+
+func TestRerankerLeavesChangedAndPendingMovies(t *testing.T) {
+	repos := repository.NewMemoryRepositories()
+	ctx := context.Background()
+
+	addRankedMovie(t, repos.Movies, "tt1", "great", testRankings[2])
+	addRankedMovie(t, repos.Movies, "tt2", "great", testRankings[2])
+
+	// tt3 has a newer review waiting in the ranking queue
+	addRankedMovie(t, repos.Movies, "tt3", "great", testRankings[2])
+
+	if err := repos.Movies.SetAdminReview(ctx, "tt3", "great", "job-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// tt2 is deleted while its review is being ranked
+	rank := func(ctx context.Context, review string) (models.Ranking, error) {
+		_ = repos.Movies.Delete(ctx, "tt2")
+		return rankByReview(ctx, review)
+	}
+
+	reranker := NewReranker(rank, repos.Movies, repos.RerankRuns)
+	run := NewRerankRun(false, 1)
+
+	if err := reranker.Run(ctx, &run, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if run.Total != 2 || run.Changed != 1 || run.Failed != 1 {
+		t.Fatalf("run = %+v, want tt1 changed and tt2 failed", run)
+	}
+
+	if len(run.Failures) != 1 || run.Failures[0].ImdbID != "tt2" || len(run.Diffs) != 1 || run.Diffs[0].ImdbID != "tt1" {
+		t.Fatalf("diffs = %+v, failures = %+v", run.Diffs, run.Failures)
+	}
+
+	pending, err := repos.Movies.Get(ctx, "tt3")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pending.Ranking != testRankings[2] || pending.RankingStatus != models.JobStatusPending {
+		t.Fatalf("pending movie = %+v, want it left to the queue", pending)
+	}
+}
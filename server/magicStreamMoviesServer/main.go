@@ -1,22 +1,118 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/config"
+	controller "github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/controllers"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/database"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/embedder"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/jobs"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/middleware"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/ranker"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/recommend"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
 	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/routes"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 )
 
 func main() {
 
-	// Create a new Gin router with defautl middleware
+	// Load the settings from env, .env and the optional CONFIG_FILE (YAML or TOML)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Unable to load configuration: ", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	// An invalid ROLE_PERMISSIONS stops the server rather than falling back to the default grants
+	permissions, err := middleware.NewRolePermissions(cfg.Auth.RolePermissions)
+	if err != nil {
+		log.Fatal("Invalid ROLE_PERMISSIONS: ", err)
+	}
+
+	// Connect to MongoDB, retrying while it starts
+	connectCtx, cancelConnect := context.WithTimeout(context.Background(), 2*time.Minute)
+	if err := database.Connect(connectCtx, cfg.Database); err != nil {
+		log.Fatal("Unable to connect to MongoDB: ", err)
+	}
+	cancelConnect()
+
+	// Create and verify the indexes the controllers rely on (unique keys, TTL, text search)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := database.EnsureIndexes(ctx); err != nil {
+		log.Fatal("Unable to ensure database indexes: ", err)
+	}
+	cancel()
+
+	// Movies, users and their data are read and written through the repositories
+	repos := repository.NewMongoRepositories(database.OpenDatabase())
+
+	// Flag the legacy 999 placeholder ranking as excluded from the AI
+	if err := controller.MigrateExcludedRankings(repos.Rankings); err != nil {
+		log.Println("Warning: unable to migrate placeholder rankings:", err)
+	}
+
+	// Create or promote the first admin from ADMIN_EMAIL/ADMIN_PASSWORD if none exists
+	if err := controller.BootstrapAdmin(repos.Users, cfg.Admin); err != nil {
+		log.Println("Warning: unable to bootstrap admin account:", err)
+	}
+
+	// Select the review ranking provider (REVIEW_RANKER=openai|ollama|lexicon|fake)
+	reviewRanker, err := ranker.FromConfig(cfg)
+	if err != nil {
+		log.Println("Warning: review ranking disabled:", err)
+		reviewRanker = nil
+	}
+
+	rankReview := func(ctx context.Context, review string) (models.Ranking, error) {
+		name, value, err := controller.GetReviewRanking(ctx, reviewRanker, repos.Rankings, review)
+		return models.Ranking{RankingName: name, RankingValue: value}, err
+	}
+
+	// Rank admin reviews in the background
+	rankingQueue := jobs.NewRankingQueue(
+		rankReview,
+		repos.Jobs,
+		repos.Movies,
+		cfg.Ranker.Workers,
+		cfg.Ranker.MaxAttempts,
+		func(err error) bool { return errors.Is(err, ranker.ErrNoValidRanking) },
+	)
+	rankingQueue.Start(context.Background())
+
+	// Bulk re-ranking of every movie when the ranking scale changes
+	reranker := jobs.NewReranker(rankReview, repos.Movies, repos.RerankRuns)
+
+	// Keep the collaborative-filtering similarities up to date in the background
+	recommender := recommend.NewEngine(
+		repos,
+		time.Duration(cfg.Recommender.IntervalSeconds)*time.Second,
+		cfg.Recommender.Neighbours,
+	)
+	recommender.Start(context.Background())
 
-	err := godotenv.Load(".env")
+	// Embed movies for "more like this" search (EMBEDDING_PROVIDER=local|openai|ollama)
+	var similarIndex *embedder.Index
 
+	movieEmbedder, err := embedder.FromConfig(cfg)
 	if err != nil {
-		log.Println("Warning: .env file not found (using system env variables)")
+		log.Println("Warning: similar movie search disabled:", err)
+	} else {
+		similarIndex = embedder.NewIndex(movieEmbedder, repos.Movies, repos.Embeddings, time.Duration(cfg.Embedding.SyncIntervalSeconds)*time.Second)
+		similarIndex.Start(context.Background())
 	}
 
 	router := gin.Default()
@@ -26,12 +122,89 @@ func main() {
 		c.String(200, "Hello, MagicStreamMovies")
 	})
 
-	routes.SetupUnProtectedRoutes(router)
+	services := &routes.Services{
+		Ping:         database.Ping,
+		Permissions:  permissions,
+		ReviewRanker: reviewRanker,
+		RankingQueue: rankingQueue,
+		Reranker:     reranker,
+		Recommender:  recommender,
+		SimilarIndex: similarIndex,
+	}
+
+	routes.SetupUnProtectedRoutes(router, repos, services, cfg)
+
+	routes.SetupProtectedRoutes(router, repos, services, cfg)
+
+	server := &http.Server{
+		Addr:              ":" + cfg.Server.Port,
+		Handler:           router,
+		ReadTimeout:       time.Duration(cfg.Server.ReadTimeoutSeconds) * time.Second,
+		ReadHeaderTimeout: time.Duration(cfg.Server.ReadHeaderTimeoutSeconds) * time.Second,
+		WriteTimeout:      time.Duration(cfg.Server.WriteTimeoutSeconds) * time.Second,
+		IdleTimeout:       time.Duration(cfg.Server.IdleTimeoutSeconds) * time.Second,
+	}
+
+	// Shut down on Ctrl+C or SIGTERM (sent by container orchestrators)
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
 
-	routes.SetupProtectedRoutes(router)
+	serverErr := make(chan error, 1)
 
-	// Start server on port 8080
-	if err := router.Run(":8080"); err != nil {
+	// Start server on the configured port (PORT, default 8080)
+	go func() {
+		log.Println("Listening on", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	failed := false
+
+	select {
+	case err := <-serverErr:
 		fmt.Println("Failed to start server", err)
+		failed = true
+	case <-signalCtx.Done():
+		log.Println("Shutting down")
+	}
+
+	// Drain in-flight requests, then stop the background workers and close
+	// the MongoDB connections they use, all within SHUTDOWN_TIMEOUT_SECONDS.
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeoutSeconds)*time.Second)
+	defer cancelShutdown()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("Warning: in-flight requests did not finish:", err)
+	}
+
+	stopWithin(shutdownCtx, "ranking queue", rankingQueue.Stop)
+	stopWithin(shutdownCtx, "re-ranker", reranker.Stop)
+	stopWithin(shutdownCtx, "recommender", recommender.Stop)
+
+	if similarIndex != nil {
+		stopWithin(shutdownCtx, "similar movie index", similarIndex.Stop)
+	}
+
+	if err := database.Disconnect(shutdownCtx); err != nil {
+		log.Println("Warning: unable to disconnect from MongoDB:", err)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// stopWithin runs stop, giving up on waiting for it once ctx is done
+func stopWithin(ctx context.Context, name string, stop func()) {
+	done := make(chan struct{})
+
+	go func() {
+		stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Printf("Warning: %s did not stop before the shutdown deadline", name)
 	}
 }
@@ -3,11 +3,14 @@ package middleware
 import (
 	"net/http"
 
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/config"
 	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/utils"
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware accepts requests carrying an unrevoked access token signed
+// with auth.SecretKey and stores its claims in the context
+func AuthMiddleware(auth config.AuthConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token, err := utils.GetAccessToken(c)
 
@@ -23,7 +26,7 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		claims, err := utils.ValidateToken(token)
+		claims, err := utils.ValidateToken(auth, token)
 
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
@@ -31,8 +34,25 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		revoked, err := utils.IsTokenRevoked(claims)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify token"})
+			c.Abort()
+			return
+		}
+
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			c.Abort()
+			return
+		}
+
 		c.Set("userId", claims.UserId)
 		c.Set("role", claims.Role)
+		c.Set("tokenId", claims.ID)
+		c.Set("tokenFamilyId", claims.FamilyId)
+		c.Set("tokenExpiresAt", claims.ExpiresAt.Time)
 
 		c.Next()
 
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Permissions understood by RolePermissions.Require
+const (
+	PermMoviesRead    = "movies:read"
+	PermMoviesWrite   = "movies:write"
+	PermReviewsWrite  = "reviews:write"
+	PermUsersAdmin    = "users:admin"
+	PermRankingsWrite = "rankings:write"
+	PermGenresWrite   = "genres:write"
+	PermRatingsWrite  = "ratings:write"
+	PermListsWrite    = "lists:write"
+	PermHistoryWrite  = "history:write"
+)
+
+// knownPermissions lists every permission checked by the protected routes
+var knownPermissions = map[string]bool{
+	PermMoviesRead:    true,
+	PermMoviesWrite:   true,
+	PermReviewsWrite:  true,
+	PermUsersAdmin:    true,
+	PermRankingsWrite: true,
+	PermGenresWrite:   true,
+	PermRatingsWrite:  true,
+	PermListsWrite:    true,
+	PermHistoryWrite:  true,
+}
+
+// defaultRolePermissions is the permission matrix ROLE_PERMISSIONS is applied to
+var defaultRolePermissions = map[string][]string{
+	"ADMIN": {PermMoviesRead, PermMoviesWrite, PermReviewsWrite, PermUsersAdmin, PermRankingsWrite, PermGenresWrite, PermRatingsWrite, PermListsWrite, PermHistoryWrite},
+	"USER":  {PermMoviesRead, PermRatingsWrite, PermListsWrite, PermHistoryWrite},
+}
+
+// RolePermissions maps each role to the permissions it is granted
+type RolePermissions map[string]map[string]bool
+
+// DefaultRolePermissions returns the default role/permission matrix
+func DefaultRolePermissions() RolePermissions {
+	matrix := make(RolePermissions, len(defaultRolePermissions))
+	for role, perms := range defaultRolePermissions {
+		matrix[role] = make(map[string]bool, len(perms))
+		for _, perm := range perms {
+			matrix[role][perm] = true
+		}
+	}
+
+	return matrix
+}
+
+// NewRolePermissions applies the ROLE_PERMISSIONS spec on top of
+// defaultRolePermissions, so permissions added to the defaults later are kept
+// by older overrides. An empty spec gives the defaults.
+func NewRolePermissions(spec string) (RolePermissions, error) {
+	matrix := DefaultRolePermissions()
+
+	if spec == "" {
+		return matrix, nil
+	}
+
+	overrides, err := ParseRolePermissions(spec)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for role, perms := range overrides {
+		if matrix[role] == nil {
+			matrix[role] = map[string]bool{}
+		}
+
+		for perm, granted := range perms {
+			if granted {
+				matrix[role][perm] = true
+			} else {
+				delete(matrix[role], perm)
+			}
+		}
+	}
+
+	return matrix, nil
+}
+
+// ParseRolePermissions parses permission overrides of the form
+// "ADMIN=movies:write;USER=reviews:write,-history:write". A permission is
+// granted to the role, or revoked when prefixed with "-"; the result maps each
+// permission to whether it is granted. Unknown permissions are rejected.
+func ParseRolePermissions(spec string) (map[string]map[string]bool, error) {
+	overrides := map[string]map[string]bool{}
+
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		role, perms, ok := strings.Cut(entry, "=")
+		role = strings.TrimSpace(role)
+
+		if !ok || role == "" {
+			return nil, fmt.Errorf("invalid role entry %q", entry)
+		}
+
+		overrides[role] = map[string]bool{}
+
+		for _, perm := range strings.Split(perms, ",") {
+			if perm = strings.TrimSpace(perm); perm == "" {
+				continue
+			}
+
+			name, revoked := strings.CutPrefix(perm, "-")
+
+			if !knownPermissions[name] {
+				return nil, fmt.Errorf("unknown permission %q for role %s", name, role)
+			}
+
+			overrides[role][name] = !revoked
+		}
+	}
+
+	if len(overrides) == 0 {
+		return nil, fmt.Errorf("no roles defined")
+	}
+
+	return overrides, nil
+}
+
+// Has reports whether the given role is granted perm
+func (p RolePermissions) Has(role, perm string) bool {
+	return p[role][perm]
+}
+
+// Require only lets the request through if the caller's role is granted
+// every one of perms. It must run after AuthMiddleware.
+func (p RolePermissions) Require(perms ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, err := utils.GetRoleFromContext(c)
+
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Role not found in context"})
+			c.Abort()
+			return
+		}
+
+		for _, perm := range perms {
+			if !p.Has(role, perm) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Missing permission " + perm})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// MovieEmbedding is the vector of a movie's text for one embedding model.
+// TextHash detects when the movie's title, genres or admin review changed.
+type MovieEmbedding struct {
+	ImdbID    string    `bson:"imdb_id" json:"imdb_id"`
+	Model     string    `bson:"model" json:"model"`
+	TextHash  string    `bson:"text_hash" json:"text_hash"`
+	Vector    []float32 `bson:"vector" json:"-"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type SimilarMovie struct {
+	Movie Movie   `json:"movie"`
+	Score float64 `json:"score"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// Health check statuses
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// DependencyStatus is the result of checking one dependency
+type DependencyStatus struct {
+	Status string `json:"status"`
+	// Provider names the implementation behind the dependency, when there is a choice
+	Provider string `json:"provider,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ReadinessReport is the /readyz response; Status is ok only when every check is ok
+type ReadinessReport struct {
+	Status string                      `json:"status"`
+	Checks map[string]DependencyStatus `json:"checks"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// WatchProgress is the latest playback state of a movie for a user; it is
+// upserted on every progress event, so there is one per user and movie.
+type WatchProgress struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	UserID    string        `bson:"user_id" json:"user_id"`
+	ImdbID    string        `bson:"imdb_id" json:"imdb_id"`
+	Position  float64       `bson:"position" json:"position"`
+	Duration  float64       `bson:"duration" json:"duration"`
+	Progress  float64       `bson:"progress" json:"progress"`
+	Completed bool          `bson:"completed" json:"completed"`
+	StartedAt time.Time     `bson:"started_at" json:"started_at"`
+	UpdatedAt time.Time     `bson:"updated_at" json:"updated_at"`
+}
+
+// PlaybackEvent is reported by the client while a trailer plays; position
+// and duration are in seconds.
+type PlaybackEvent struct {
+	ImdbID   string  `json:"imdb_id" validate:"required"`
+	Position float64 `json:"position" validate:"gte=0,ltefield=Duration"`
+	Duration float64 `json:"duration" validate:"gt=0"`
+}
+
+// HistoryEntry is a watch progress entry with the movie's details joined in
+type HistoryEntry struct {
+	ImdbID    string    `bson:"imdb_id" json:"imdb_id"`
+	Position  float64   `bson:"position" json:"position"`
+	Duration  float64   `bson:"duration" json:"duration"`
+	Progress  float64   `bson:"progress" json:"progress"`
+	Completed bool      `bson:"completed" json:"completed"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+	Movie     Movie     `bson:"movie" json:"movie"`
+}
+
+type HistoryPage struct {
+	History []HistoryEntry `json:"history"`
+	Total   int64          `json:"total"`
+	Page    int64          `json:"page"`
+	Limit   int64          `json:"limit"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Ranking job and movie ranking statuses
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// RankingJob is a queued classification of a movie's admin review
+type RankingJob struct {
+	ID            bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	ImdbID        string        `bson:"imdb_id" json:"imdb_id"`
+	AdminReview   string        `bson:"admin_review" json:"admin_review"`
+	Status        string        `bson:"status" json:"status"`
+	Attempts      int           `bson:"attempts" json:"attempts"`
+	LastError     string        `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	Ranking       *Ranking      `bson:"ranking,omitempty" json:"ranking,omitempty"`
+	NextAttemptAt time.Time     `bson:"next_attempt_at" json:"next_attempt_at"`
+	CreatedAt     time.Time     `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time     `bson:"updated_at" json:"updated_at"`
+	// LeaseExpiresAt is set while the job is running; once it passes, the worker
+	// holding the job is presumed dead and the job can be claimed again
+	LeaseExpiresAt *time.Time `bson:"lease_expires_at,omitempty" json:"lease_expires_at,omitempty"`
+}
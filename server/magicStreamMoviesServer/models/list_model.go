@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Personal movie lists a user can keep
+const (
+	ListWatchlist  = "watchlist"
+	ListFavourites = "favourites"
+)
+
+// ListItem is one movie saved in a user's watchlist or favourites.
+// Position is only meaningful for the watchlist, which the user can reorder.
+type ListItem struct {
+	ID       bson.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	UserID   string        `bson:"user_id" json:"user_id"`
+	List     string        `bson:"list" json:"list"`
+	ImdbID   string        `bson:"imdb_id" json:"imdb_id"`
+	Position int           `bson:"position" json:"position"`
+	AddedAt  time.Time     `bson:"added_at" json:"added_at"`
+}
+
+type ListItemInput struct {
+	ImdbID string `json:"imdb_id" validate:"required"`
+}
+
+// ListEntry is a list item with the saved movie's details joined in
+type ListEntry struct {
+	ImdbID   string    `bson:"imdb_id" json:"imdb_id"`
+	Position int       `bson:"position" json:"position"`
+	AddedAt  time.Time `bson:"added_at" json:"added_at"`
+	Movie    Movie     `bson:"movie" json:"movie"`
+}
+
+type ListOrder struct {
+	Order []string `json:"order" validate:"required,min=1,dive,required"`
+}
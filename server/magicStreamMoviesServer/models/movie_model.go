@@ -9,18 +9,91 @@ type Genre struct {
 	GenreName string `bson:"genre_name" json:"genre_name" validate:"required,min=2,max=100"`
 }
 
+// Ranking is an entry of the rankings scale; values start at 1 and lower
+// values are better.
+// Rankings with ExcludedFromAI set (e.g. a "not ranked yet" placeholder) are
+// never offered to the review ranker. The flag is stored even when false so
+// the legacy 999 migration only touches rankings that predate it.
 type Ranking struct {
-	RankingValue int    `bson:"ranking_value" json:"ranking_value" validate:"required"`
-	RankingName  string `bson:"ranking_name" json:"ranking_name" validate:"oneof=Excellent Good Okay Bad Terrible"`
+	RankingValue   int    `bson:"ranking_value" json:"ranking_value" validate:"required,min=1"`
+	RankingName    string `bson:"ranking_name" json:"ranking_name" validate:"required,min=1,max=50"`
+	ExcludedFromAI bool   `bson:"excluded_from_ai" json:"excluded_from_ai,omitempty"`
+}
+
+// RankingOrder lists ranking names from best to worst for reordering the scale
+type RankingOrder struct {
+	Order []string `json:"order" validate:"required,min=1,dive,required"`
 }
 
 type Movie struct {
-	ID          bson.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
-	ImdbID      string        `bson:"imdb_id" json:"imdb_id" validate:"required"`
-	Title       string        `bson:"title" json:"title" validate:"required,min=2,max=100"`
-	PosterPath  string        `bson:"poster_path" json:"poster_path" validate:"required,url"`
-	YouTubeID   string        `bson:"youtube_id" json:"youtube_id" validate:"required"`
-	Genre       []Genre       `bson:"genre" json:"genre" validate:"required,dive"`
-	AdminReview string        `bson:"admin_review" json:"admin_review"`
-	Ranking     Ranking       `bson:"ranking" json:"ranking" validate:"required"`
+	ID            bson.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	ImdbID        string        `bson:"imdb_id" json:"imdb_id" validate:"required"`
+	Title         string        `bson:"title" json:"title" validate:"required,min=2,max=100"`
+	PosterPath    string        `bson:"poster_path" json:"poster_path" validate:"required,url"`
+	YouTubeID     string        `bson:"youtube_id" json:"youtube_id" validate:"required"`
+	Genre         []Genre       `bson:"genre" json:"genre" validate:"required,dive"`
+	AdminReview   string        `bson:"admin_review" json:"admin_review"`
+	Ranking       Ranking       `bson:"ranking" json:"ranking" validate:"required"`
+	RankingStatus string        `bson:"ranking_status,omitempty" json:"ranking_status,omitempty"`
+	RankingJobID  string        `bson:"ranking_job_id,omitempty" json:"ranking_job_id,omitempty"`
+	AverageRating float64       `bson:"average_rating" json:"average_rating"`
+	RatingCount   int           `bson:"rating_count" json:"rating_count"`
+}
+
+// MoviePatch holds a partial movie update; nil fields are left unchanged.
+// The admin review and its ranking are not part of it: they only change
+// through the review update, which queues the ranking.
+type MoviePatch struct {
+	ImdbID     *string  `json:"imdb_id" validate:"omitempty,min=1"`
+	Title      *string  `json:"title" validate:"omitempty,min=2,max=100"`
+	PosterPath *string  `json:"poster_path" validate:"omitempty,url"`
+	YouTubeID  *string  `json:"youtube_id" validate:"omitempty,min=1"`
+	Genre      *[]Genre `json:"genre" validate:"omitempty,min=1,dive"`
+}
+
+// MoviePatchRequest is the body of PATCH /movie/:imdb_id; admin_review and
+// ranking are only decoded so that they can be rejected.
+type MoviePatchRequest struct {
+	MoviePatch
+	AdminReview *string  `json:"admin_review"`
+	Ranking     *Ranking `json:"ranking"`
+}
+
+// MoviePage is the envelope returned by paginated movie listings
+type MoviePage struct {
+	Movies     []Movie `json:"movies"`
+	Total      int64   `json:"total"`
+	Page       int64   `json:"page,omitempty"`
+	Limit      int64   `json:"limit"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// MovieSearchResult is a single hit returned by the movie search endpoint
+type MovieSearchResult struct {
+	Movie      Movie             `json:"movie"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// GenreInput is the body for creating or renaming a genre; a zero GenreID on
+// create means the next free id is assigned.
+type GenreInput struct {
+	GenreID   int    `json:"genre_id" validate:"omitempty,min=1"`
+	GenreName string `json:"genre_name" validate:"required,min=2,max=100"`
+}
+
+// GenreMergeReport summarises a run of the duplicate genre merge migration
+type GenreMergeReport struct {
+	Genres        []Genre           `json:"genres"`
+	Renamed       map[string]string `json:"renamed"`
+	Collisions    []GenreCollision  `json:"collisions"`
+	MoviesUpdated int64             `json:"movies_updated"`
+	UsersUpdated  int64             `json:"users_updated"`
+}
+
+// GenreCollision lists differently named genres that share one genre_id and
+// so could not be merged automatically
+type GenreCollision struct {
+	GenreID    int      `json:"genre_id"`
+	GenreNames []string `json:"genre_names"`
 }
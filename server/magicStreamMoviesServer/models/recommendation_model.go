@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Interaction is the combined implicit feedback of a user for a movie,
+// derived from their rating, lists and watch history.
+type Interaction struct {
+	UserID    string    `bson:"user_id" json:"user_id"`
+	ImdbID    string    `bson:"imdb_id" json:"imdb_id"`
+	Weight    float64   `bson:"weight" json:"weight"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type Neighbour struct {
+	ImdbID string  `bson:"imdb_id" json:"imdb_id"`
+	Score  float64 `bson:"score" json:"score"`
+}
+
+// MovieSimilarity holds a movie's most similar movies by item-item
+// collaborative filtering, best first.
+type MovieSimilarity struct {
+	ImdbID     string      `bson:"imdb_id" json:"imdb_id"`
+	Neighbours []Neighbour `bson:"neighbours" json:"neighbours"`
+	UpdatedAt  time.Time   `bson:"updated_at" json:"updated_at"`
+}
+
+// Recommendation is a recommended movie with why it was chosen. The movie's
+// fields are inlined so clients reading plain movies keep working.
+type Recommendation struct {
+	Movie
+	Score     float64              `json:"score"`
+	Breakdown ScoreBreakdown       `json:"score_breakdown"`
+	Reason    RecommendationReason `json:"reason"`
+}
+
+// ScoreBreakdown shows how Score was computed:
+// Score = CollaborativeWeight*Collaborative + (1-CollaborativeWeight)*Genre, where
+// Genre blends GenreMatch and Ranking.
+type ScoreBreakdown struct {
+	Collaborative       float64 `json:"collaborative"`
+	CollaborativeWeight float64 `json:"collaborative_weight"`
+	Genre               float64 `json:"genre"`
+	GenreMatch          float64 `json:"genre_match"`
+	Ranking             float64 `json:"ranking"`
+}
+
+type RecommendationReason struct {
+	// Summary is a short sentence a client can show, e.g. "Because you like Thriller"
+	Summary       string         `json:"summary"`
+	MatchedGenres []string       `json:"matched_genres"`
+	RankingName   string         `json:"ranking_name,omitempty"`
+	SimilarTo     []SimilarTitle `json:"similar_to"`
+}
+
+// SimilarTitle is a movie the user liked that led to a recommendation, with
+// how much it contributed to the collaborative score.
+type SimilarTitle struct {
+	ImdbID       string  `json:"imdb_id"`
+	Title        string  `json:"title"`
+	Contribution float64 `json:"contribution"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// RankingDiff records a movie whose ranking changed (or would change) during a re-rank.
+// Diffs are stored in their own collection, keyed by the run they belong to.
+type RankingDiff struct {
+	RunID  bson.ObjectID `bson:"run_id" json:"-"`
+	ImdbID string        `bson:"imdb_id" json:"imdb_id"`
+	Title  string        `bson:"title" json:"title"`
+	Old    Ranking       `bson:"old" json:"old"`
+	New    Ranking       `bson:"new" json:"new"`
+}
+
+// RerankFailure records a movie that could not be re-ranked; like diffs,
+// failures are stored in their own collection keyed by run.
+type RerankFailure struct {
+	RunID  bson.ObjectID `bson:"run_id" json:"-"`
+	ImdbID string        `bson:"imdb_id" json:"imdb_id"`
+	Error  string        `bson:"error" json:"error"`
+}
+
+// RerankRun is a bulk re-classification of every movie's admin review.
+// The run document only keeps counts so it stays small however many movies
+// there are; Diffs and Failures hold a page of the entries stored for the run.
+type RerankRun struct {
+	ID          bson.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Status      string          `bson:"status" json:"status"`
+	DryRun      bool            `bson:"dry_run" json:"dry_run"`
+	Concurrency int             `bson:"concurrency" json:"concurrency"`
+	Total       int64           `bson:"total" json:"total"`
+	Processed   int64           `bson:"processed" json:"processed"`
+	Changed     int64           `bson:"changed" json:"changed"`
+	Failed      int64           `bson:"failed" json:"failed"`
+	Diffs       []RankingDiff   `bson:"-" json:"diffs"`
+	Failures    []RerankFailure `bson:"-" json:"failures"`
+	Error       string          `bson:"error,omitempty" json:"error,omitempty"`
+	StartedAt   time.Time       `bson:"started_at" json:"started_at"`
+	FinishedAt  *time.Time      `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+}
+
+type RerankRequest struct {
+	DryRun      bool `json:"dry_run"`
+	Concurrency int  `json:"concurrency" validate:"omitempty,min=1,max=32"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// UserReview is a user's star rating and optional text review of a movie;
+// each user has at most one review per movie.
+type UserReview struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	UserID    string        `bson:"user_id" json:"user_id"`
+	ImdbID    string        `bson:"imdb_id" json:"imdb_id"`
+	Rating    int           `bson:"rating" json:"rating"`
+	Text      string        `bson:"text" json:"text"`
+	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time     `bson:"updated_at" json:"updated_at"`
+}
+
+type UserReviewInput struct {
+	Rating int    `json:"rating" validate:"required,min=1,max=5"`
+	Text   string `json:"text" validate:"max=2000"`
+}
+
+// UserReviewPage is the envelope returned when listing a movie's reviews
+type UserReviewPage struct {
+	Reviews []UserReview `json:"reviews"`
+	Total   int64        `json:"total"`
+	Page    int64        `json:"page"`
+	Limit   int64        `json:"limit"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// RevokedToken is an entry in the token denylist: it revokes a single token by
+// its jti. Entries are removed by a TTL index once ExpiresAt has passed.
+type RevokedToken struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	TokenID   string        `bson:"jti,omitempty" json:"jti,omitempty"`
+	UserID    string        `bson:"user_id" json:"user_id"`
+	ExpiresAt time.Time     `bson:"expires_at" json:"expires_at"`
+}
+
+// TokenFamily is one login session. Every login starts a new family and each
+// refresh rotates RefreshToken within it, so several devices can stay signed
+// in at once. A family is deleted on logout, when one of its rotated refresh
+// tokens is presented again, and by a TTL index once ExpiresAt has passed.
+type TokenFamily struct {
+	ID           bson.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	FamilyID     string        `bson:"family_id" json:"family_id"`
+	UserID       string        `bson:"user_id" json:"user_id"`
+	RefreshToken string        `bson:"refresh_token" json:"-"`
+	CreatedAt    time.Time     `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time     `bson:"updated_at" json:"updated_at"`
+	ExpiresAt    time.Time     `bson:"expires_at" json:"expires_at"`
+}
@@ -14,6 +14,7 @@ type User struct {
 	Email           string        `json:"email" bson:"email" validate:"required,email"`
 	Password        string        `json:"password" bson:"pasword" validate:"required,min=6"`
 	Role            string        `json:"role" bson:"role" validate:"oneof=ADMIN USER"`
+	Disabled        bool          `json:"disabled" bson:"disabled"`
 	CreatedAt       time.Time     `json:"created_at" bson:"created_at"`
 	UpdatedAt       time.Time     `json:"updated_at" bson:"updated_at"`
 	Token           string        `json:"token" bson:"token"`
@@ -36,3 +37,24 @@ type UserResponse struct {
 	RefreshToken    string  `json:"refresh_token"`
 	FavouriteGenres []Genre `json:"favourite_genres"`
 }
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// UserSummary is the admin view of a user; it never carries the password hash or tokens
+type UserSummary struct {
+	UserID          string    `json:"user_id" bson:"user_id"`
+	FirstName       string    `json:"first_name" bson:"first_name"`
+	LastName        string    `json:"last_name" bson:"last_name"`
+	Email           string    `json:"email" bson:"email"`
+	Role            string    `json:"role" bson:"role"`
+	Disabled        bool      `json:"disabled" bson:"disabled"`
+	CreatedAt       time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" bson:"updated_at"`
+	FavouriteGenres []Genre   `json:"favourite_genres" bson:"favourite_genres"`
+}
+
+type UserRoleUpdate struct {
+	Role string `json:"role" validate:"required,oneof=ADMIN USER"`
+}
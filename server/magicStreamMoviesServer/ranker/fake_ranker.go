@@ -0,0 +1,54 @@
+package ranker
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+// FakeRanker is a ReviewRanker for tests. It answers with the ranking matching
+// Response as a model answer would be parsed (or the first ranking when Response
+// is empty), or returns Err, and records every review it was asked to rank.
+type FakeRanker struct {
+	Response string
+	Err      error
+
+	mu      sync.Mutex
+	reviews []string
+}
+
+// RankReview returns the configured answer
+func (f *FakeRanker) RankReview(ctx context.Context, review string, rankings []models.Ranking) (models.Ranking, error) {
+	f.mu.Lock()
+	f.reviews = append(f.reviews, review)
+	f.mu.Unlock()
+
+	if f.Err != nil {
+		return models.Ranking{}, f.Err
+	}
+
+	if len(rankings) == 0 {
+		return models.Ranking{}, errors.New("no rankings available")
+	}
+
+	if f.Response == "" {
+		return rankings[0], nil
+	}
+
+	return ParseRankingResponse(f.Response, rankings)
+}
+
+// Provider returns fake
+func (f *FakeRanker) Provider() string {
+	return ProviderFake
+}
+
+// Reviews returns the reviews passed to RankReview so far
+func (f *FakeRanker) Reviews() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.reviews...)
+}
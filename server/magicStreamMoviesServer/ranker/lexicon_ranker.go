@@ -0,0 +1,112 @@
+package ranker
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+// defaultLexicon assigns a sentiment weight in [-2, 2] to common review words
+var defaultLexicon = map[string]float64{
+	"masterpiece": 2, "excellent": 2, "outstanding": 2, "brilliant": 2, "superb": 2,
+	"amazing": 2, "fantastic": 2, "perfect": 2, "wonderful": 2, "incredible": 2,
+	"great": 1.5, "loved": 1.5, "love": 1.5, "awesome": 1.5, "best": 1.5,
+	"good": 1, "enjoyable": 1, "enjoyed": 1, "fun": 1, "entertaining": 1, "nice": 1,
+	"solid": 1, "fine": 0.5, "decent": 0.5, "okay": 0.25, "ok": 0.25, "average": 0,
+	"mediocre": -0.5, "boring": -1, "dull": -1, "slow": -0.5, "weak": -1,
+	"bad": -1.5, "poor": -1.5, "disappointing": -1.5, "disappointed": -1.5,
+	"terrible": -2, "awful": -2, "horrible": -2, "worst": -2, "garbage": -2, "hated": -2,
+}
+
+// negations flip the sentiment of the words that follow them
+var negations = map[string]bool{
+	"not": true, "no": true, "never": true, "isn't": true, "wasn't": true,
+	"don't": true, "didn't": true, "hardly": true, "nothing": true,
+}
+
+// intensifiers scale the sentiment of the next sentiment word
+var intensifiers = map[string]float64{
+	"very": 1.5, "really": 1.5, "extremely": 2, "so": 1.3, "truly": 1.5, "quite": 1.2,
+	"slightly": 0.5, "somewhat": 0.6,
+}
+
+// negationWindow is how many following words a negation applies to
+const negationWindow = 3
+
+// LexiconRanker is an offline, deterministic ranker based on a word lexicon.
+// It scores the review between -1 and 1 and maps the score linearly onto the
+// rankings ordered by ranking_value (lowest value is the best ranking).
+type LexiconRanker struct {
+	lexicon map[string]float64
+}
+
+// NewLexiconRanker creates a LexiconRanker with the built-in lexicon
+func NewLexiconRanker() *LexiconRanker {
+	return &LexiconRanker{lexicon: defaultLexicon}
+}
+
+// Provider returns lexicon
+func (r *LexiconRanker) Provider() string {
+	return ProviderLexicon
+}
+
+// Score returns the sentiment of review in [-1, 1]; 0 means neutral or unknown
+func (r *LexiconRanker) Score(review string) float64 {
+	words := strings.FieldsFunc(strings.ToLower(review), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+
+	total, hits := 0.0, 0
+	negateFor := 0
+	boost := 1.0
+
+	for _, word := range words {
+		if negations[word] {
+			negateFor = negationWindow
+			continue
+		}
+
+		if factor, ok := intensifiers[word]; ok {
+			boost = factor
+			continue
+		}
+
+		if weight, ok := r.lexicon[word]; ok {
+			weight *= boost
+			if negateFor > 0 {
+				weight = -weight
+			}
+			total += weight
+			hits++
+			boost = 1.0
+		}
+
+		if negateFor > 0 {
+			negateFor--
+		}
+	}
+
+	if hits == 0 {
+		return 0
+	}
+
+	// average weight is in [-4, 4] with intensifiers; squash into [-1, 1]
+	return math.Max(-1, math.Min(1, total/float64(hits)/2))
+}
+
+// RankReview maps the lexicon score of review onto one of rankings
+func (r *LexiconRanker) RankReview(ctx context.Context, review string, rankings []models.Ranking) (models.Ranking, error) {
+	if len(rankings) == 0 {
+		return models.Ranking{}, errors.New("no rankings available")
+	}
+
+	sorted := sortedByValue(rankings)
+
+	index := int(math.Round((1 - r.Score(review)) / 2 * float64(len(sorted)-1)))
+
+	return sorted[index], nil
+}
@@ -0,0 +1,155 @@
+package ranker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/ollama"
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+// DefaultPromptTemplate is used when BASE_PROMPT_TEMPLATE is empty.
+// {rankings} is replaced with the comma separated ranking names.
+const DefaultPromptTemplate = "Return a response using one of these words: {rankings}. " +
+	"The response should be a single word and should not contain any other text. " +
+	"The response should be based on the following review: "
+
+// LLMRanker ranks reviews by prompting a language model
+type LLMRanker struct {
+	model          llms.Model
+	promptTemplate string
+	provider       string
+
+	// MaxRetries is how many corrective prompts are sent after an invalid answer
+	MaxRetries int
+}
+
+// DefaultMaxRetries is the number of corrective prompts sent by default
+const DefaultMaxRetries = 2
+
+// NewLLMRanker wraps any langchaingo model as a ReviewRanker; its provider is "llm"
+func NewLLMRanker(model llms.Model, promptTemplate string) *LLMRanker {
+	if promptTemplate == "" {
+		promptTemplate = DefaultPromptTemplate
+	}
+
+	return &LLMRanker{model: model, promptTemplate: promptTemplate, provider: "llm", MaxRetries: DefaultMaxRetries}
+}
+
+// NewOpenAIRanker creates an LLMRanker backed by OpenAI
+func NewOpenAIRanker(apiKey, model, promptTemplate string) (*LLMRanker, error) {
+	if apiKey == "" {
+		return nil, errors.New("could not read OPENAI_API_KEY")
+	}
+
+	opts := []openai.Option{openai.WithToken(apiKey)}
+
+	if model != "" {
+		opts = append(opts, openai.WithModel(model))
+	}
+
+	llm, err := openai.New(opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r := NewLLMRanker(llm, promptTemplate)
+	r.provider = ProviderOpenAI
+
+	return r, nil
+}
+
+// NewOllamaRanker creates an LLMRanker backed by an Ollama-compatible HTTP endpoint
+func NewOllamaRanker(serverURL, model, promptTemplate string) (*LLMRanker, error) {
+	if model == "" {
+		return nil, errors.New("could not read OLLAMA_MODEL")
+	}
+
+	opts := []ollama.Option{ollama.WithModel(model)}
+
+	if serverURL != "" {
+		opts = append(opts, ollama.WithServerURL(serverURL))
+	}
+
+	llm, err := ollama.New(opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r := NewLLMRanker(llm, promptTemplate)
+	r.provider = ProviderOllama
+
+	return r, nil
+}
+
+// Provider returns openai, ollama or, for other models, llm
+func (r *LLMRanker) Provider() string {
+	return r.provider
+}
+
+// RankReview asks the model to pick one of the ranking names for review.
+// The model is asked for a JSON answer; if the answer cannot be matched to a
+// ranking the model is told what was wrong and asked again, up to MaxRetries
+// times, before ErrNoValidRanking is returned.
+func (r *LLMRanker) RankReview(ctx context.Context, review string, rankings []models.Ranking) (models.Ranking, error) {
+	if len(rankings) == 0 {
+		return models.Ranking{}, errors.New("no rankings available")
+	}
+
+	names := make([]string, 0, len(rankings))
+
+	for _, ranking := range rankings {
+		names = append(names, ranking.RankingName)
+	}
+
+	rankingList := strings.Join(names, ",")
+
+	// Replace placeholder with actual ranking list
+	prompt := strings.Replace(r.promptTemplate, "{rankings}", rankingList, 1) + review +
+		"\n\nRespond only with a JSON object of the form {\"ranking\": \"<one of: " + rankingList + ">\"}."
+
+	messages := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, prompt),
+	}
+
+	var lastErr error
+
+	for attempt := 0; attempt <= r.MaxRetries; attempt++ {
+		resp, err := r.model.GenerateContent(ctx, messages, llms.WithJSONMode(), llms.WithTemperature(0))
+
+		if err != nil {
+			return models.Ranking{}, err
+		}
+
+		if len(resp.Choices) == 0 {
+			return models.Ranking{}, errors.New("empty response from model")
+		}
+
+		answer := resp.Choices[0].Content
+
+		ranking, err := ParseRankingResponse(answer, rankings)
+
+		if err == nil {
+			return ranking, nil
+		}
+
+		lastErr = err
+
+		// Show the model its invalid answer and ask for a corrected one
+		messages = append(messages,
+			llms.TextParts(llms.ChatMessageTypeAI, answer),
+			llms.TextParts(llms.ChatMessageTypeHuman, fmt.Sprintf(
+				"%q is not a valid answer. Reply with exactly one of these rankings: %s, "+
+					"as a JSON object of the form {\"ranking\": \"<ranking>\"} and nothing else.",
+				answer, rankingList)),
+		)
+	}
+
+	return models.Ranking{}, lastErr
+}
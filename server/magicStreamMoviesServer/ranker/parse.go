@@ -0,0 +1,152 @@
+package ranker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+// ErrNoValidRanking is returned when a ranker's answer cannot be matched to any ranking
+var ErrNoValidRanking = errors.New("no valid ranking could be derived from the review")
+
+// responseKeys are the JSON keys accepted as the ranking in a structured answer
+var responseKeys = []string{"ranking", "ranking_name", "rankingName", "sentiment", "answer"}
+
+// ParseRankingResponse matches a model answer against rankings.
+// It accepts a JSON object such as {"ranking": "Good"} or plain text, ignores
+// case, whitespace and punctuation ("Good.", " good\n"), accepts an answer
+// that mentions exactly one ranking name, and finally tolerates small typos.
+func ParseRankingResponse(response string, rankings []models.Ranking) (models.Ranking, error) {
+	candidate := extractJSONRanking(response)
+
+	if candidate == "" {
+		candidate = response
+	}
+
+	normalized := normalizeAnswer(candidate)
+
+	if normalized == "" {
+		return models.Ranking{}, fmt.Errorf("%w: empty answer", ErrNoValidRanking)
+	}
+
+	// exact match after normalization
+	for _, ranking := range rankings {
+		if normalizeAnswer(ranking.RankingName) == normalized {
+			return ranking, nil
+		}
+	}
+
+	// the answer mentions exactly one ranking name as a whole word
+	words := " " + normalized + " "
+	var mentioned []models.Ranking
+
+	for _, ranking := range rankings {
+		if name := normalizeAnswer(ranking.RankingName); name != "" && strings.Contains(words, " "+name+" ") {
+			mentioned = append(mentioned, ranking)
+		}
+	}
+
+	if len(mentioned) == 1 {
+		return mentioned[0], nil
+	}
+
+	// a single word close to exactly one ranking name, e.g. "Excelent"
+	if !strings.Contains(normalized, " ") {
+		best, bestDistance, ties := models.Ranking{}, -1, 0
+
+		for _, ranking := range rankings {
+			name := normalizeAnswer(ranking.RankingName)
+			distance := levenshtein(normalized, name)
+
+			if distance > maxTypos(name) {
+				continue
+			}
+
+			switch {
+			case bestDistance < 0 || distance < bestDistance:
+				best, bestDistance, ties = ranking, distance, 1
+			case distance == bestDistance:
+				ties++
+			}
+		}
+
+		if bestDistance >= 0 && ties == 1 {
+			return best, nil
+		}
+	}
+
+	return models.Ranking{}, fmt.Errorf("%w: %q", ErrNoValidRanking, strings.TrimSpace(response))
+}
+
+// extractJSONRanking returns the ranking from a JSON object embedded in response, if any
+func extractJSONRanking(response string) string {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+
+	if start < 0 || end <= start {
+		return ""
+	}
+
+	var payload map[string]interface{}
+
+	if err := json.Unmarshal([]byte(response[start:end+1]), &payload); err != nil {
+		return ""
+	}
+
+	for _, key := range responseKeys {
+		if value, ok := payload[key].(string); ok {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// normalizeAnswer lower-cases s, drops punctuation and collapses whitespace
+func normalizeAnswer(s string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, s)
+
+	return strings.Join(strings.Fields(cleaned), " ")
+}
+
+// maxTypos is the edit distance tolerated for a ranking name of this length
+func maxTypos(name string) int {
+	if n := len([]rune(name)); n > 4 {
+		return n / 4
+	}
+	return 1
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
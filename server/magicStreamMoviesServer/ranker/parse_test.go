@@ -0,0 +1,143 @@
+package ranker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+var testRankings = []models.Ranking{
+	{RankingValue: 1, RankingName: "Excellent"},
+	{RankingValue: 2, RankingName: "Good"},
+	{RankingValue: 3, RankingName: "Okay"},
+	{RankingValue: 4, RankingName: "Bad"},
+	{RankingValue: 5, RankingName: "Terrible"},
+}
+
+func TestParseRankingResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+		wantErr  bool
+	}{
+		{name: "exact", response: "Good", want: "Good"},
+		{name: "case and punctuation", response: " good.\n", want: "Good"},
+		{name: "json object", response: `{"ranking": "Excellent"}`, want: "Excellent"},
+		{name: "json after text", response: `Sure! {"sentiment": "bad"}`, want: "Bad"},
+		{name: "json alternative key", response: `{"ranking_name": "Okay"}`, want: "Okay"},
+		{name: "json non string value", response: `{"ranking": 3}`, wantErr: true},
+		{name: "whole word mention", response: "The review is Terrible overall", want: "Terrible"},
+		{name: "several mentions", response: "Good or bad", wantErr: true},
+		{name: "mention inside a word", response: "goodness gracious", wantErr: true},
+		{name: "typo", response: "Excelent", want: "Excellent"},
+		{name: "typo in short name", response: "Okey", want: "Okay"},
+		{name: "too many typos", response: "Goodness", wantErr: true},
+		{name: "empty", response: "", wantErr: true},
+		{name: "only punctuation", response: " ...\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRankingResponse(tt.response, testRankings)
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrNoValidRanking) {
+					t.Fatalf("ParseRankingResponse(%q) error = %v, want ErrNoValidRanking", tt.response, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseRankingResponse(%q) unexpected error: %v", tt.response, err)
+			}
+
+			if got.RankingName != tt.want {
+				t.Errorf("ParseRankingResponse(%q) = %q, want %q", tt.response, got.RankingName, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeAnswer(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Good", want: "good"},
+		{in: "  Very\tGOOD!! ", want: "very good"},
+		{in: "so-so", want: "so so"},
+		{in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeAnswer(tt.in); got != tt.want {
+			t.Errorf("normalizeAnswer(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "good", b: "", want: 4},
+		{a: "good", b: "good", want: 0},
+		{a: "excelent", b: "excellent", want: 1},
+		{a: "okey", b: "okay", want: 1},
+		{a: "kitten", b: "sitting", want: 3},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFakeRanker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("first ranking by default", func(t *testing.T) {
+		fake := &FakeRanker{}
+
+		got, err := fake.RankReview(ctx, "a review", testRankings)
+
+		if err != nil || got.RankingName != "Excellent" {
+			t.Fatalf("RankReview() = %v, %v; want Excellent", got, err)
+		}
+
+		if reviews := fake.Reviews(); len(reviews) != 1 || reviews[0] != "a review" {
+			t.Errorf("Reviews() = %v, want [a review]", reviews)
+		}
+	})
+
+	t.Run("parses response", func(t *testing.T) {
+		fake := &FakeRanker{Response: `{"ranking": "bad"}`}
+
+		got, err := fake.RankReview(ctx, "a review", testRankings)
+
+		if err != nil || got.RankingName != "Bad" {
+			t.Fatalf("RankReview() = %v, %v; want Bad", got, err)
+		}
+	})
+
+	t.Run("returns error", func(t *testing.T) {
+		want := errors.New("model unavailable")
+		fake := &FakeRanker{Err: want}
+
+		if _, err := fake.RankReview(ctx, "a review", testRankings); !errors.Is(err, want) {
+			t.Fatalf("RankReview() error = %v, want %v", err, want)
+		}
+	})
+
+	t.Run("no rankings", func(t *testing.T) {
+		if _, err := (&FakeRanker{}).RankReview(ctx, "a review", nil); err == nil {
+			t.Fatal("RankReview() without rankings succeeded, want error")
+		}
+	})
+}
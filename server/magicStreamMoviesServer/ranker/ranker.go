@@ -0,0 +1,76 @@
+package ranker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/config"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+// Ranker providers selectable with REVIEW_RANKER
+const (
+	ProviderOpenAI  = "openai"
+	ProviderOllama  = "ollama"
+	ProviderLexicon = "lexicon"
+	ProviderFake    = "fake"
+)
+
+// ReviewRanker classifies an admin review into one of the given rankings
+type ReviewRanker interface {
+	RankReview(ctx context.Context, review string, rankings []models.Ranking) (models.Ranking, error)
+	// Provider returns the name of the provider doing the ranking
+	Provider() string
+}
+
+// FromConfig builds the ReviewRanker selected by the ranker provider (REVIEW_RANKER):
+//
+//	openai  - OpenAI chat model (OPENAI_API_KEY, optional OPENAI_MODEL); the default
+//	ollama  - Ollama-compatible HTTP endpoint (OLLAMA_SERVER_URL, OLLAMA_MODEL)
+//	lexicon - offline keyword/lexicon sentiment ranker
+//	fake    - always answers with the first ranking, for local development
+//
+// RANKER_MAX_RETRIES sets how often the LLM rankers re-prompt after an invalid answer.
+func FromConfig(cfg *config.Config) (ReviewRanker, error) {
+	promptTemplate := cfg.Ranker.PromptTemplate
+
+	var llmRanker *LLMRanker
+	var err error
+
+	switch provider := cfg.Ranker.Provider; provider {
+	case "", ProviderOpenAI:
+		llmRanker, err = NewOpenAIRanker(cfg.OpenAI.APIKey, cfg.OpenAI.Model, promptTemplate)
+	case ProviderOllama:
+		llmRanker, err = NewOllamaRanker(cfg.Ollama.ServerURL, cfg.Ollama.Model, promptTemplate)
+	case ProviderLexicon:
+		return NewLexiconRanker(), nil
+	case ProviderFake:
+		return &FakeRanker{}, nil
+	default:
+		return nil, fmt.Errorf("unknown REVIEW_RANKER %q", provider)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Ranker.MaxRetries < 0 {
+		return nil, fmt.Errorf("invalid RANKER_MAX_RETRIES %d", cfg.Ranker.MaxRetries)
+	}
+
+	llmRanker.MaxRetries = cfg.Ranker.MaxRetries
+
+	return llmRanker, nil
+}
+
+// sortedByValue returns a copy of rankings ordered by ranking_value, best first
+func sortedByValue(rankings []models.Ranking) []models.Ranking {
+	sorted := append([]models.Ranking(nil), rankings...)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].RankingValue < sorted[j].RankingValue
+	})
+
+	return sorted
+}
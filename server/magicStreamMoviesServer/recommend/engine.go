@@ -0,0 +1,282 @@
+package recommend
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+)
+
+// DefaultNeighbours is how many similar movies are kept per movie by default
+const DefaultNeighbours = 50
+
+// Engine keeps the item-item similarities up to date. Controllers mark users
+// whose feedback changed with MarkDirty; every interval the engine refreshes
+// those users' interactions and recomputes the similarities of the movies
+// they touched, so work is proportional to what changed.
+type Engine struct {
+	repos      *repository.Repositories
+	interval   time.Duration
+	neighbours int
+
+	mu          sync.Mutex
+	dirty       map[string]bool
+	dirtyMovies map[string]bool
+
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+}
+
+// NewEngine creates an engine that reads feedback from repos, flushes changes
+// every interval and keeps the given number of neighbours per movie.
+func NewEngine(repos *repository.Repositories, interval time.Duration, neighbours int) *Engine {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	if neighbours < 1 {
+		neighbours = DefaultNeighbours
+	}
+
+	return &Engine{repos: repos, interval: interval, neighbours: neighbours, dirty: map[string]bool{}, dirtyMovies: map[string]bool{}}
+}
+
+// MarkDirty schedules the user's interactions for recomputation
+func (e *Engine) MarkDirty(userId string) {
+	e.mu.Lock()
+	e.dirty[userId] = true
+	e.mu.Unlock()
+}
+
+// Start launches the background refresh loop. When no similarities have been
+// computed yet, every user with feedback is scheduled first.
+func (e *Engine) Start(ctx context.Context) {
+	ctx, e.cancel = context.WithCancel(ctx)
+
+	e.wg.Add(1)
+
+	go func() {
+		defer e.wg.Done()
+
+		e.seed(ctx)
+
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				// flush what is still pending so it is not lost on shutdown
+				flushCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				if err := e.Flush(flushCtx); err != nil {
+					log.Println("Warning: unable to flush recommendations on shutdown:", err)
+				}
+				cancel()
+				return
+			case <-ticker.C:
+				if err := e.Flush(ctx); err != nil && ctx.Err() == nil {
+					log.Println("Warning: unable to refresh recommendations:", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the refresh loop and waits for the pending changes to be flushed.
+func (e *Engine) Stop() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+
+	e.wg.Wait()
+}
+
+// seed schedules every user when the similarity collection is empty
+func (e *Engine) seed(ctx context.Context) {
+	seedCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	count, err := e.repos.Similarities.Count(seedCtx)
+
+	if err != nil || count > 0 {
+		return
+	}
+
+	users, err := interactingUsers(seedCtx, e.repos)
+
+	if err != nil {
+		log.Println("Warning: unable to list users for recommendations:", err)
+		return
+	}
+
+	for _, userId := range users {
+		e.MarkDirty(userId)
+	}
+}
+
+// Flush recomputes the interactions of the dirty users and the similarities
+// of every movie whose interactions changed. Work that fails stays scheduled
+// for the next flush.
+func (e *Engine) Flush(ctx context.Context) error {
+	e.mu.Lock()
+	users := e.dirty
+	e.dirty = map[string]bool{}
+	e.mu.Unlock()
+
+	var firstErr error
+
+	for userId := range users {
+		changed, err := e.refreshUser(ctx, userId)
+
+		if err != nil {
+			e.MarkDirty(userId)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		e.mu.Lock()
+		for imdbId := range changed {
+			e.dirtyMovies[imdbId] = true
+		}
+		e.mu.Unlock()
+	}
+
+	e.mu.Lock()
+	movies := e.dirtyMovies
+	e.dirtyMovies = map[string]bool{}
+	e.mu.Unlock()
+
+	for imdbId := range movies {
+		if err := e.recomputeMovie(ctx, imdbId); err != nil {
+			e.mu.Lock()
+			e.dirtyMovies[imdbId] = true
+			e.mu.Unlock()
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// refreshUser rematerialises a user's interactions and returns the movies whose weight changed
+func (e *Engine) refreshUser(ctx context.Context, userId string) (map[string]bool, error) {
+	previous, err := storedInteractions(ctx, e.repos.Interactions, userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := sourceInteractions(ctx, e.repos, userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	changed := map[string]bool{}
+
+	for imdbId, weight := range current {
+		if previous[imdbId] != weight {
+			changed[imdbId] = true
+		}
+	}
+
+	for imdbId := range previous {
+		if _, ok := current[imdbId]; !ok {
+			changed[imdbId] = true
+		}
+	}
+
+	if len(changed) == 0 {
+		return changed, nil
+	}
+
+	return changed, e.repos.Interactions.Replace(ctx, userId, current)
+}
+
+// recomputeMovie recomputes the cosine similarity between imdbId and every
+// movie that shares a user with it, stores its top neighbours and updates the
+// reverse entries in the other movies' neighbour lists.
+func (e *Engine) recomputeMovie(ctx context.Context, imdbId string) error {
+	raters, err := e.repos.Interactions.ListByMovie(ctx, imdbId)
+
+	if err != nil {
+		return err
+	}
+
+	userWeights := make(map[string]float64, len(raters))
+	userIds := make([]string, 0, len(raters))
+
+	for _, interaction := range raters {
+		userWeights[interaction.UserID] = interaction.Weight
+		userIds = append(userIds, interaction.UserID)
+	}
+
+	dots := map[string]float64{}
+
+	if len(userIds) > 0 {
+		related, err := e.repos.Interactions.ListByUsers(ctx, userIds)
+
+		if err != nil {
+			return err
+		}
+
+		for _, interaction := range related {
+			if interaction.ImdbID != imdbId {
+				dots[interaction.ImdbID] += userWeights[interaction.UserID] * interaction.Weight
+			}
+		}
+	}
+
+	candidates := make([]string, 0, len(dots)+1)
+
+	for other := range dots {
+		candidates = append(candidates, other)
+	}
+
+	norms, err := e.repos.Interactions.Norms(ctx, append(candidates, imdbId))
+
+	if err != nil {
+		return err
+	}
+
+	neighbours := []models.Neighbour{}
+
+	for _, other := range candidates {
+		if norms[imdbId] == 0 || norms[other] == 0 {
+			continue
+		}
+
+		score := dots[other] / (norms[imdbId] * norms[other])
+
+		if score > 0 {
+			neighbours = append(neighbours, models.Neighbour{ImdbID: other, Score: score})
+		}
+	}
+
+	sort.Slice(neighbours, func(i, j int) bool { return neighbours[i].Score > neighbours[j].Score })
+
+	scores := make(map[string]float64, len(neighbours))
+
+	for _, neighbour := range neighbours {
+		scores[neighbour.ImdbID] = neighbour.Score
+	}
+
+	// replace imdbId's entry in the neighbour list of every movie it was or now is similar to
+	if err := e.repos.Similarities.ReplaceNeighbour(ctx, imdbId, scores, e.neighbours); err != nil {
+		return err
+	}
+
+	if len(neighbours) > e.neighbours {
+		neighbours = neighbours[:e.neighbours]
+	}
+
+	return e.repos.Similarities.Save(ctx, imdbId, neighbours)
+}
@@ -0,0 +1,151 @@
+package recommend
+
+import (
+	"context"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+)
+
+// Feedback weights. A rating counts from -1 (one star) to +1 (five stars);
+// list membership and playback only ever count as positive signal.
+const (
+	favouriteWeight = 1.0
+	watchlistWeight = 0.5
+	completedWeight = 0.8
+	// partial playback counts proportionally to the watched fraction, up to this weight
+	partialWeight = 0.6
+)
+
+// ratingWeight maps a 1-5 star rating onto -1..+1
+func ratingWeight(rating int) float64 {
+	return (float64(rating) - 3) / 2
+}
+
+// sourceInteractions combines a user's reviews, lists and watch history into
+// one weight per movie.
+func sourceInteractions(ctx context.Context, repos *repository.Repositories, userId string) (map[string]float64, error) {
+	weights := map[string]float64{}
+
+	reviews, err := repos.Reviews.ListByUser(ctx, userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, review := range reviews {
+		weights[review.ImdbID] += ratingWeight(review.Rating)
+	}
+
+	items, err := repos.Lists.Items(ctx, userId, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if item.List == models.ListFavourites {
+			weights[item.ImdbID] += favouriteWeight
+		} else {
+			weights[item.ImdbID] += watchlistWeight
+		}
+	}
+
+	history, err := repos.History.ListByUser(ctx, userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, watched := range history {
+		if watched.Completed {
+			weights[watched.ImdbID] += completedWeight
+		} else {
+			weights[watched.ImdbID] += partialWeight * watched.Progress
+		}
+	}
+
+	for imdbId, weight := range weights {
+		if weight == 0 {
+			delete(weights, imdbId)
+		}
+	}
+
+	return weights, nil
+}
+
+// seenMovies returns the movies a user has already watched or rated; they are
+// never recommended back to them.
+func seenMovies(ctx context.Context, repos *repository.Repositories, userId string) (map[string]bool, error) {
+	seen := map[string]bool{}
+
+	reviews, err := repos.Reviews.ListByUser(ctx, userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, review := range reviews {
+		seen[review.ImdbID] = true
+	}
+
+	history, err := repos.History.ListByUser(ctx, userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, watched := range history {
+		if watched.Completed {
+			seen[watched.ImdbID] = true
+		}
+	}
+
+	return seen, nil
+}
+
+// storedInteractions returns the interactions last materialised for a user
+func storedInteractions(ctx context.Context, interactionRepo repository.InteractionRepository, userId string) (map[string]float64, error) {
+	interactions, err := interactionRepo.ListByUser(ctx, userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	weights := make(map[string]float64, len(interactions))
+
+	for _, interaction := range interactions {
+		weights[interaction.ImdbID] = interaction.Weight
+	}
+
+	return weights, nil
+}
+
+// interactingUsers returns the distinct users that have any feedback source
+func interactingUsers(ctx context.Context, repos *repository.Repositories) ([]string, error) {
+	users := map[string]bool{}
+
+	sources := []func(ctx context.Context) ([]string, error){
+		repos.Reviews.Users, repos.Lists.Users, repos.History.Users, repos.Interactions.Users,
+	}
+
+	for _, source := range sources {
+		ids, err := source(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			users[id] = true
+		}
+	}
+
+	result := make([]string, 0, len(users))
+
+	for id := range users {
+		result = append(result, id)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,340 @@
+package recommend
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+)
+
+const (
+	// coldStartInteractions is how many liked movies a user needs before
+	// collaborative filtering fully replaces the genre-based score
+	coldStartInteractions = 5
+	// genreCandidates is how many genre matches are considered per requested recommendation
+	genreCandidates = 4
+	// genreMatchShare is the part of the genre score given by the genre match,
+	// the rest comes from the movie's ranking
+	genreMatchShare = 0.7
+	// maxSimilarTo caps how many liked movies are listed in a recommendation's reason
+	maxSimilarTo = 3
+)
+
+// candidate is a movie being scored, with the liked movies that contributed
+// to its collaborative score
+type candidate struct {
+	movie         models.Movie
+	cf            float64
+	contributions map[string]float64
+}
+
+// Recommend returns up to limit movies for the user, each explained by a
+// score breakdown and a reason. Collaborative filtering scores from the
+// user's interactions are blended with the genre-based score from
+// favouriteGenres; users with few interactions get mostly genre-based
+// results. When both give fewer than limit movies the best ranked movies fill
+// the rest. Movies the user already watched or rated are excluded.
+func Recommend(ctx context.Context, repos *repository.Repositories, userId string, favouriteGenres []string, limit int64) ([]models.Recommendation, error) {
+	weights, err := storedInteractions(ctx, repos.Interactions, userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	seen, err := seenMovies(ctx, repos, userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cfScores, contributions, err := collaborativeScores(ctx, repos.Similarities, weights, seen)
+
+	if err != nil {
+		return nil, err
+	}
+
+	liked := []string{}
+
+	for imdbId, weight := range weights {
+		if weight > 0 {
+			liked = append(liked, imdbId)
+		}
+	}
+
+	alpha := math.Min(1, float64(len(liked))/coldStartInteractions)
+
+	candidates := map[string]*candidate{}
+
+	cfIds := make([]string, 0, len(cfScores))
+
+	for imdbId := range cfScores {
+		cfIds = append(cfIds, imdbId)
+	}
+
+	if len(cfIds) > 0 {
+		movies, err := repos.Movies.List(ctx, repository.MovieQuery{ImdbIDs: cfIds})
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, movie := range movies {
+			candidates[movie.ImdbID] = &candidate{
+				movie:         movie,
+				cf:            cfScores[movie.ImdbID],
+				contributions: contributions[movie.ImdbID],
+			}
+		}
+	}
+
+	excluded := make([]string, 0, len(seen))
+
+	for imdbId := range seen {
+		excluded = append(excluded, imdbId)
+	}
+
+	if len(favouriteGenres) > 0 {
+		query := repository.MovieQuery{
+			GenreNames:     favouriteGenres,
+			ExcludeImdbIDs: excluded,
+			Limit:          limit * genreCandidates,
+		}
+
+		if err := addCandidates(ctx, repos.Movies, candidates, query); err != nil {
+			return nil, err
+		}
+	}
+
+	// backfill with the best ranked movies so the user always gets limit
+	// recommendations while the other signals are sparse
+	if missing := limit - int64(len(candidates)); missing > 0 {
+		taken := append([]string{}, excluded...)
+
+		for imdbId := range candidates {
+			taken = append(taken, imdbId)
+		}
+
+		ranked := 1
+
+		query := repository.MovieQuery{
+			MinRanking:     &ranked,
+			ExcludeImdbIDs: taken,
+			Limit:          missing,
+		}
+
+		if err := addCandidates(ctx, repos.Movies, candidates, query); err != nil {
+			return nil, err
+		}
+	}
+
+	titles, err := movieTitles(ctx, repos.Movies, liked)
+
+	if err != nil {
+		return nil, err
+	}
+
+	favourites := make(map[string]bool, len(favouriteGenres))
+
+	for _, name := range favouriteGenres {
+		favourites[name] = true
+	}
+
+	results := make([]models.Recommendation, 0, len(candidates))
+
+	for _, c := range candidates {
+		results = append(results, explain(c, alpha, favourites, titles))
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Ranking.RankingValue < results[j].Ranking.RankingValue
+	})
+
+	if int64(len(results)) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// addCandidates adds the movies matching query, best ranked first, to
+// candidates without collaborative scores
+func addCandidates(ctx context.Context, movieRepo repository.MovieRepository, candidates map[string]*candidate, query repository.MovieQuery) error {
+	query.SortField = repository.SortByRanking
+
+	movies, err := movieRepo.List(ctx, query)
+
+	if err != nil {
+		return err
+	}
+
+	for _, movie := range movies {
+		if _, ok := candidates[movie.ImdbID]; !ok {
+			candidates[movie.ImdbID] = &candidate{movie: movie}
+		}
+	}
+
+	return nil
+}
+
+// explain scores a candidate and records which signals produced the score
+func explain(c *candidate, alpha float64, favourites map[string]bool, titles map[string]string) models.Recommendation {
+	matched := []string{}
+
+	for _, genre := range c.movie.Genre {
+		if favourites[genre.GenreName] {
+			matched = append(matched, genre.GenreName)
+		}
+	}
+
+	genreMatch := 0.0
+
+	if len(c.movie.Genre) > 0 {
+		genreMatch = float64(len(matched)) / float64(len(c.movie.Genre))
+	}
+
+	ranking := rankingScore(c.movie.Ranking)
+	genre := genreMatchShare*genreMatch + (1-genreMatchShare)*ranking
+
+	similarTo := []models.SimilarTitle{}
+
+	for imdbId, contribution := range c.contributions {
+		similarTo = append(similarTo, models.SimilarTitle{ImdbID: imdbId, Title: titles[imdbId], Contribution: contribution})
+	}
+
+	sort.Slice(similarTo, func(i, j int) bool { return similarTo[i].Contribution > similarTo[j].Contribution })
+
+	if len(similarTo) > maxSimilarTo {
+		similarTo = similarTo[:maxSimilarTo]
+	}
+
+	reason := models.RecommendationReason{
+		MatchedGenres: matched,
+		SimilarTo:     similarTo,
+	}
+
+	if !c.movie.Ranking.ExcludedFromAI {
+		reason.RankingName = c.movie.Ranking.RankingName
+	}
+
+	cfPart := alpha * c.cf
+	genrePart := (1 - alpha) * genre
+
+	switch {
+	case cfPart >= genrePart && len(similarTo) > 0:
+		reason.Summary = "Because you liked " + similarTo[0].Title
+	case len(matched) > 0:
+		reason.Summary = "Because you like " + strings.Join(matched, " and ")
+	case reason.RankingName != "":
+		reason.Summary = "Rated " + reason.RankingName
+	default:
+		reason.Summary = "Recommended for you"
+	}
+
+	return models.Recommendation{
+		Movie: c.movie,
+		Score: cfPart + genrePart,
+		Breakdown: models.ScoreBreakdown{
+			Collaborative:       c.cf,
+			CollaborativeWeight: alpha,
+			Genre:               genre,
+			GenreMatch:          genreMatch,
+			Ranking:             ranking,
+		},
+		Reason: reason,
+	}
+}
+
+// collaborativeScores scores the neighbours of the movies the user liked by
+// the user's weight times the similarity, scaled so the best score is 1. It
+// also returns, per scored movie, the share each liked movie contributed.
+func collaborativeScores(ctx context.Context, similarityRepo repository.SimilarityRepository, weights map[string]float64, seen map[string]bool) (map[string]float64, map[string]map[string]float64, error) {
+	liked := make([]string, 0, len(weights))
+
+	for imdbId, weight := range weights {
+		if weight > 0 {
+			liked = append(liked, imdbId)
+		}
+	}
+
+	scores := map[string]float64{}
+	contributions := map[string]map[string]float64{}
+
+	if len(liked) == 0 {
+		return scores, contributions, nil
+	}
+
+	similarities, err := similarityRepo.ListByMovies(ctx, liked)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	best := 0.0
+
+	for _, similarity := range similarities {
+		for _, neighbour := range similarity.Neighbours {
+			if seen[neighbour.ImdbID] || weights[neighbour.ImdbID] != 0 {
+				continue
+			}
+
+			contribution := weights[similarity.ImdbID] * neighbour.Score
+
+			if contributions[neighbour.ImdbID] == nil {
+				contributions[neighbour.ImdbID] = map[string]float64{}
+			}
+
+			contributions[neighbour.ImdbID][similarity.ImdbID] = contribution
+			scores[neighbour.ImdbID] += contribution
+			best = math.Max(best, scores[neighbour.ImdbID])
+		}
+	}
+
+	if best > 0 {
+		for imdbId := range scores {
+			scores[imdbId] /= best
+
+			for source := range contributions[imdbId] {
+				contributions[imdbId][source] /= best
+			}
+		}
+	}
+
+	return scores, contributions, nil
+}
+
+// movieTitles returns the titles of the given movies by imdb_id
+func movieTitles(ctx context.Context, movieRepo repository.MovieRepository, imdbIds []string) (map[string]string, error) {
+	titles := make(map[string]string, len(imdbIds))
+
+	if len(imdbIds) == 0 {
+		return titles, nil
+	}
+
+	movies, err := movieRepo.List(ctx, repository.MovieQuery{ImdbIDs: imdbIds})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, movie := range movies {
+		titles[movie.ImdbID] = movie.Title
+	}
+
+	return titles, nil
+}
+
+// rankingScore maps ranking_value 1 (best) to 1 and worse rankings towards 0;
+// rankings excluded from the AI carry no signal.
+func rankingScore(ranking models.Ranking) float64 {
+	if ranking.ExcludedFromAI || ranking.RankingValue < 1 {
+		return 0
+	}
+
+	return 1 / float64(ranking.RankingValue)
+}
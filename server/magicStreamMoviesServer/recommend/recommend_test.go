@@ -0,0 +1,69 @@
+package recommend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
+)
+
+func TestRecommend(t *testing.T) {
+	ctx := context.Background()
+	repos := repository.NewMemoryRepositories()
+
+	action := models.Genre{GenreID: 1, GenreName: "Action"}
+	drama := models.Genre{GenreID: 2, GenreName: "Drama"}
+
+	movies := []models.Movie{
+		{ImdbID: "tt1", Title: "Heat", Genre: []models.Genre{action}, Ranking: models.Ranking{RankingValue: 1, RankingName: "Excellent"}},
+		{ImdbID: "tt2", Title: "Ronin", Genre: []models.Genre{action}, Ranking: models.Ranking{RankingValue: 3, RankingName: "Okay"}},
+		{ImdbID: "tt3", Title: "Collateral", Genre: []models.Genre{drama}, Ranking: models.Ranking{RankingValue: 2, RankingName: "Good"}},
+		{ImdbID: "tt4", Title: "Thief", Genre: []models.Genre{drama}, Ranking: models.Ranking{RankingValue: 1, RankingName: "Excellent"}},
+	}
+
+	for i := range movies {
+		if err := repos.Movies.Insert(ctx, &movies[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the user rated Heat, so it is liked and never recommended back
+	if err := repos.Reviews.Insert(ctx, &models.UserReview{UserID: "u1", ImdbID: "tt1", Rating: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repos.Interactions.Replace(ctx, "u1", map[string]float64{"tt1": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repos.Similarities.Save(ctx, "tt1", []models.Neighbour{{ImdbID: "tt2", Score: 0.9}}); err != nil {
+		t.Fatal(err)
+	}
+
+	recommendations, err := Recommend(ctx, repos, "u1", nil, 3)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recommendations) != 3 {
+		t.Fatalf("got %d recommendations, want 3 with the backfill", len(recommendations))
+	}
+
+	for _, recommendation := range recommendations {
+		if recommendation.Movie.ImdbID == "tt1" {
+			t.Fatal("a movie the user already rated was recommended")
+		}
+	}
+
+	top := recommendations[0]
+
+	if top.Movie.ImdbID != "tt2" || len(top.Reason.SimilarTo) != 1 || top.Reason.SimilarTo[0].Title != "Heat" {
+		t.Fatalf("top recommendation = %+v, want Ronin because of Heat", top)
+	}
+
+	if recommendations[1].Movie.ImdbID != "tt4" {
+		t.Fatalf("second recommendation = %s, want the best ranked backfill tt4", recommendations[1].Movie.ImdbID)
+	}
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+// embeddingKey identifies the embedding of a movie made with a model
+type embeddingKey struct {
+	imdbId string
+	model  string
+}
+
+// MemoryEmbeddingRepository keeps movie embeddings in memory. It is safe for concurrent use.
+type MemoryEmbeddingRepository struct {
+	mu         sync.RWMutex
+	embeddings map[embeddingKey]models.MovieEmbedding
+}
+
+// NewMemoryEmbeddingRepository creates an empty in-memory EmbeddingRepository
+func NewMemoryEmbeddingRepository() *MemoryEmbeddingRepository {
+	return &MemoryEmbeddingRepository{embeddings: map[embeddingKey]models.MovieEmbedding{}}
+}
+
+func (r *MemoryEmbeddingRepository) Get(ctx context.Context, imdbId, model string) (models.MovieEmbedding, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	embedding, ok := r.embeddings[embeddingKey{imdbId, model}]
+
+	if !ok {
+		return models.MovieEmbedding{}, ErrNotFound
+	}
+
+	embedding.Vector = slices.Clone(embedding.Vector)
+
+	return embedding, nil
+}
+
+func (r *MemoryEmbeddingRepository) ListByModel(ctx context.Context, model string) ([]models.MovieEmbedding, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	embeddings := []models.MovieEmbedding{}
+
+	for key, embedding := range r.embeddings {
+		if key.model == model {
+			embedding.Vector = slices.Clone(embedding.Vector)
+			embeddings = append(embeddings, embedding)
+		}
+	}
+
+	return embeddings, nil
+}
+
+func (r *MemoryEmbeddingRepository) Hashes(ctx context.Context, model string) (map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hashes := map[string]string{}
+
+	for key, embedding := range r.embeddings {
+		if key.model == model {
+			hashes[key.imdbId] = embedding.TextHash
+		}
+	}
+
+	return hashes, nil
+}
+
+func (r *MemoryEmbeddingRepository) Save(ctx context.Context, embeddings []models.MovieEmbedding) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, embedding := range embeddings {
+		embedding.Vector = slices.Clone(embedding.Vector)
+		r.embeddings[embeddingKey{embedding.ImdbID, embedding.Model}] = embedding
+	}
+
+	return nil
+}
+
+func (r *MemoryEmbeddingRepository) DeleteMovies(ctx context.Context, imdbIds []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.embeddings {
+		if slices.Contains(imdbIds, key.imdbId) {
+			delete(r.embeddings, key)
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryEmbeddingRepository) DeleteOtherModels(ctx context.Context, model string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.embeddings {
+		if key.model != model {
+			delete(r.embeddings, key)
+		}
+	}
+
+	return nil
+}
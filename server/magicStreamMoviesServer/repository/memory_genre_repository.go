@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+// MemoryGenreRepository keeps genres in memory. It is safe for concurrent use.
+type MemoryGenreRepository struct {
+	mu     sync.RWMutex
+	genres map[int]models.Genre
+}
+
+// NewMemoryGenreRepository creates an in-memory GenreRepository holding genres
+func NewMemoryGenreRepository(genres ...models.Genre) *MemoryGenreRepository {
+	r := &MemoryGenreRepository{genres: map[int]models.Genre{}}
+
+	for _, genre := range genres {
+		r.genres[genre.GenreID] = genre
+	}
+
+	return r
+}
+
+// nameTaken reports whether a genre other than the one with skipId has the
+// name key of name; the caller holds the lock
+func (r *MemoryGenreRepository) nameTaken(name string, skipId int) bool {
+	key := GenreNameKey(name)
+
+	for id, genre := range r.genres {
+		if id != skipId && GenreNameKey(genre.GenreName) == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *MemoryGenreRepository) List(ctx context.Context) ([]models.Genre, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	genres := make([]models.Genre, 0, len(r.genres))
+
+	for _, genre := range r.genres {
+		genres = append(genres, genre)
+	}
+
+	sort.Slice(genres, func(i, j int) bool { return genres[i].GenreName < genres[j].GenreName })
+
+	return genres, nil
+}
+
+func (r *MemoryGenreRepository) GetMany(ctx context.Context, ids []int) ([]models.Genre, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	genres := []models.Genre{}
+
+	for id, genre := range r.genres {
+		if slices.Contains(ids, id) {
+			genres = append(genres, genre)
+		}
+	}
+
+	return genres, nil
+}
+
+func (r *MemoryGenreRepository) Insert(ctx context.Context, genre models.Genre) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.genres[genre.GenreID]; ok || r.nameTaken(genre.GenreName, genre.GenreID) {
+		return ErrDuplicate
+	}
+
+	r.genres[genre.GenreID] = genre
+
+	return nil
+}
+
+func (r *MemoryGenreRepository) NextID(ctx context.Context) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	next := 1
+
+	for id := range r.genres {
+		next = max(next, id+1)
+	}
+
+	return next, nil
+}
+
+func (r *MemoryGenreRepository) Update(ctx context.Context, genre models.Genre) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.genres[genre.GenreID]; !ok {
+		return ErrNotFound
+	}
+
+	if r.nameTaken(genre.GenreName, genre.GenreID) {
+		return ErrDuplicate
+	}
+
+	r.genres[genre.GenreID] = genre
+
+	return nil
+}
+
+func (r *MemoryGenreRepository) Delete(ctx context.Context, genreId int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.genres[genreId]; !ok {
+		return ErrNotFound
+	}
+
+	delete(r.genres, genreId)
+
+	return nil
+}
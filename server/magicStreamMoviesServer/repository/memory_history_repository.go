@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// MemoryHistoryRepository keeps users' watch history in memory and joins in
+// the movies of a MemoryMovieRepository. It is safe for concurrent use.
+type MemoryHistoryRepository struct {
+	movies *MemoryMovieRepository
+
+	mu      sync.RWMutex
+	history []models.WatchProgress
+}
+
+// NewMemoryHistoryRepository creates an empty in-memory HistoryRepository reading movies from movies
+func NewMemoryHistoryRepository(movies *MemoryMovieRepository) *MemoryHistoryRepository {
+	return &MemoryHistoryRepository{movies: movies}
+}
+
+// matchesHistory reports whether watched passes the filters of query
+func matchesHistory(watched models.WatchProgress, query HistoryQuery) bool {
+	return watched.UserID == query.UserID &&
+		!(query.Unfinished && watched.Completed) &&
+		watched.Progress >= query.MinProgress
+}
+
+// filterHistory returns the entries matching query, most recent first; the caller holds the lock
+func (r *MemoryHistoryRepository) filterHistory(query HistoryQuery) []models.WatchProgress {
+	history := []models.WatchProgress{}
+
+	for _, watched := range r.history {
+		if matchesHistory(watched, query) {
+			history = append(history, watched)
+		}
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		if !history[i].UpdatedAt.Equal(history[j].UpdatedAt) {
+			return history[i].UpdatedAt.After(history[j].UpdatedAt)
+		}
+		return history[i].ID.Hex() > history[j].ID.Hex()
+	})
+
+	return history
+}
+
+func (r *MemoryHistoryRepository) Record(ctx context.Context, watched models.WatchProgress) (models.WatchProgress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.history, func(existing models.WatchProgress) bool {
+		return existing.UserID == watched.UserID && existing.ImdbID == watched.ImdbID
+	})
+
+	if i < 0 {
+		watched.ID = bson.NewObjectID()
+		watched.StartedAt = watched.UpdatedAt
+		r.history = append(r.history, watched)
+		return watched, nil
+	}
+
+	watched.ID = r.history[i].ID
+	watched.StartedAt = r.history[i].StartedAt
+	r.history[i] = watched
+
+	return watched, nil
+}
+
+func (r *MemoryHistoryRepository) List(ctx context.Context, query HistoryQuery) ([]models.HistoryEntry, error) {
+	r.mu.RLock()
+	history := page(r.filterHistory(query), query.Skip, query.Limit)
+	r.mu.RUnlock()
+
+	entries := []models.HistoryEntry{}
+
+	for _, watched := range history {
+		movie, err := r.movies.Get(ctx, watched.ImdbID)
+
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, models.HistoryEntry{
+			ImdbID:    watched.ImdbID,
+			Position:  watched.Position,
+			Duration:  watched.Duration,
+			Progress:  watched.Progress,
+			Completed: watched.Completed,
+			UpdatedAt: watched.UpdatedAt,
+			Movie:     movie,
+		})
+	}
+
+	return entries, nil
+}
+
+func (r *MemoryHistoryRepository) Count(ctx context.Context, query HistoryQuery) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.filterHistory(query))), nil
+}
+
+func (r *MemoryHistoryRepository) ListByUser(ctx context.Context, userId string) ([]models.WatchProgress, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.filterHistory(HistoryQuery{UserID: userId}), nil
+}
+
+func (r *MemoryHistoryRepository) Delete(ctx context.Context, userId, imdbId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.history, func(watched models.WatchProgress) bool {
+		return watched.UserID == userId && watched.ImdbID == imdbId
+	})
+
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	r.history = slices.Delete(r.history, i, i+1)
+
+	return nil
+}
+
+func (r *MemoryHistoryRepository) Users(ctx context.Context) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]string, 0, len(r.history))
+
+	for _, watched := range r.history {
+		users = append(users, watched.UserID)
+	}
+
+	return distinct(users), nil
+}
+
+func (r *MemoryHistoryRepository) MoveMovie(ctx context.Context, fromImdbId, toImdbId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.history {
+		if r.history[i].ImdbID == fromImdbId {
+			r.history[i].ImdbID = toImdbId
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryHistoryRepository) DeleteMovie(ctx context.Context, imdbId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history = slices.DeleteFunc(r.history, func(watched models.WatchProgress) bool { return watched.ImdbID == imdbId })
+
+	return nil
+}
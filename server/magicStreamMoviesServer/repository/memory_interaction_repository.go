@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+// MemoryInteractionRepository keeps the materialised user interactions in memory. It is safe for concurrent use.
+type MemoryInteractionRepository struct {
+	mu           sync.RWMutex
+	interactions []models.Interaction
+}
+
+// NewMemoryInteractionRepository creates an empty in-memory InteractionRepository
+func NewMemoryInteractionRepository() *MemoryInteractionRepository {
+	return &MemoryInteractionRepository{}
+}
+
+// filterInteractions returns the interactions for which match returns true
+func (r *MemoryInteractionRepository) filterInteractions(match func(interaction models.Interaction) bool) []models.Interaction {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	interactions := []models.Interaction{}
+
+	for _, interaction := range r.interactions {
+		if match(interaction) {
+			interactions = append(interactions, interaction)
+		}
+	}
+
+	return interactions
+}
+
+func (r *MemoryInteractionRepository) ListByUser(ctx context.Context, userId string) ([]models.Interaction, error) {
+	return r.filterInteractions(func(interaction models.Interaction) bool { return interaction.UserID == userId }), nil
+}
+
+func (r *MemoryInteractionRepository) ListByMovie(ctx context.Context, imdbId string) ([]models.Interaction, error) {
+	return r.filterInteractions(func(interaction models.Interaction) bool { return interaction.ImdbID == imdbId }), nil
+}
+
+func (r *MemoryInteractionRepository) ListByUsers(ctx context.Context, userIds []string) ([]models.Interaction, error) {
+	return r.filterInteractions(func(interaction models.Interaction) bool { return slices.Contains(userIds, interaction.UserID) }), nil
+}
+
+func (r *MemoryInteractionRepository) Replace(ctx context.Context, userId string, weights map[string]float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interactions = slices.DeleteFunc(r.interactions, func(interaction models.Interaction) bool { return interaction.UserID == userId })
+
+	now := time.Now()
+
+	for imdbId, weight := range weights {
+		r.interactions = append(r.interactions, models.Interaction{UserID: userId, ImdbID: imdbId, Weight: weight, UpdatedAt: now})
+	}
+
+	return nil
+}
+
+func (r *MemoryInteractionRepository) Norms(ctx context.Context, imdbIds []string) (map[string]float64, error) {
+	squares := map[string]float64{}
+
+	for _, interaction := range r.filterInteractions(func(interaction models.Interaction) bool { return slices.Contains(imdbIds, interaction.ImdbID) }) {
+		squares[interaction.ImdbID] += interaction.Weight * interaction.Weight
+	}
+
+	norms := make(map[string]float64, len(squares))
+
+	for imdbId, square := range squares {
+		norms[imdbId] = math.Sqrt(square)
+	}
+
+	return norms, nil
+}
+
+func (r *MemoryInteractionRepository) Users(ctx context.Context) ([]string, error) {
+	users := []string{}
+
+	for _, interaction := range r.filterInteractions(func(models.Interaction) bool { return true }) {
+		users = append(users, interaction.UserID)
+	}
+
+	return distinct(users), nil
+}
+
+func (r *MemoryInteractionRepository) MoveMovie(ctx context.Context, fromImdbId, toImdbId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.interactions {
+		if r.interactions[i].ImdbID == fromImdbId {
+			r.interactions[i].ImdbID = toImdbId
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryInteractionRepository) DeleteMovie(ctx context.Context, imdbId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interactions = slices.DeleteFunc(r.interactions, func(interaction models.Interaction) bool { return interaction.ImdbID == imdbId })
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// MemoryListRepository keeps users' watchlists and favourites in memory and
+// joins in the movies of a MemoryMovieRepository. It is safe for concurrent use.
+type MemoryListRepository struct {
+	movies *MemoryMovieRepository
+
+	mu    sync.RWMutex
+	items []models.ListItem
+}
+
+// NewMemoryListRepository creates an empty in-memory ListRepository reading movies from movies
+func NewMemoryListRepository(movies *MemoryMovieRepository) *MemoryListRepository {
+	return &MemoryListRepository{movies: movies}
+}
+
+// inList reports whether item belongs to the user's list, or to any of their lists when list is empty
+func inList(item models.ListItem, userId, list string) bool {
+	return item.UserID == userId && (list == "" || item.List == list)
+}
+
+func (r *MemoryListRepository) Entries(ctx context.Context, userId, list string) ([]models.ListEntry, error) {
+	items, err := r.Items(ctx, userId, list)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if list == models.ListFavourites {
+		sort.Slice(items, func(i, j int) bool { return items[i].AddedAt.After(items[j].AddedAt) })
+	} else {
+		sort.Slice(items, func(i, j int) bool { return items[i].Position < items[j].Position })
+	}
+
+	entries := []models.ListEntry{}
+
+	for _, item := range items {
+		movie, err := r.movies.Get(ctx, item.ImdbID)
+
+		// movies deleted since they were saved drop out of the list
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, models.ListEntry{ImdbID: item.ImdbID, Position: item.Position, AddedAt: item.AddedAt, Movie: movie})
+	}
+
+	return entries, nil
+}
+
+func (r *MemoryListRepository) Items(ctx context.Context, userId, list string) ([]models.ListItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := []models.ListItem{}
+
+	for _, item := range r.items {
+		if inList(item, userId, list) {
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+func (r *MemoryListRepository) Add(ctx context.Context, item *models.ListItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	position := 1
+
+	for _, existing := range r.items {
+		if !inList(existing, item.UserID, item.List) {
+			continue
+		}
+
+		if existing.ImdbID == item.ImdbID {
+			return ErrDuplicate
+		}
+
+		position = max(position, existing.Position+1)
+	}
+
+	item.ID = bson.NewObjectID()
+	item.Position = position
+	r.items = append(r.items, *item)
+
+	return nil
+}
+
+func (r *MemoryListRepository) Remove(ctx context.Context, userId, list, imdbId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.items, func(item models.ListItem) bool {
+		return inList(item, userId, list) && item.ImdbID == imdbId
+	})
+
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	r.items = slices.Delete(r.items, i, i+1)
+
+	return nil
+}
+
+func (r *MemoryListRepository) Reorder(ctx context.Context, userId, list string, order []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.items {
+		if !inList(r.items[i], userId, list) {
+			continue
+		}
+
+		if position := slices.Index(order, r.items[i].ImdbID); position >= 0 {
+			r.items[i].Position = position + 1
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryListRepository) Users(ctx context.Context) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]string, 0, len(r.items))
+
+	for _, item := range r.items {
+		users = append(users, item.UserID)
+	}
+
+	return distinct(users), nil
+}
+
+func (r *MemoryListRepository) MoveMovie(ctx context.Context, fromImdbId, toImdbId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.items {
+		if r.items[i].ImdbID == fromImdbId {
+			r.items[i].ImdbID = toImdbId
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryListRepository) DeleteMovie(ctx context.Context, imdbId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items = slices.DeleteFunc(r.items, func(item models.ListItem) bool { return item.ImdbID == imdbId })
+
+	return nil
+}
@@ -0,0 +1,457 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// MemoryMovieRepository keeps movies in memory. It is safe for concurrent use.
+type MemoryMovieRepository struct {
+	mu     sync.RWMutex
+	movies map[string]models.Movie
+}
+
+// NewMemoryMovieRepository creates an empty in-memory MovieRepository
+func NewMemoryMovieRepository() *MemoryMovieRepository {
+	return &MemoryMovieRepository{movies: map[string]models.Movie{}}
+}
+
+// cloneMovie copies the slices of movie so callers cannot modify the stored movie
+func cloneMovie(movie models.Movie) models.Movie {
+	movie.Genre = slices.Clone(movie.Genre)
+	return movie
+}
+
+// matchesMovie reports whether movie passes the filters of query
+func matchesMovie(movie models.Movie, query MovieQuery) bool {
+	if len(query.GenreNames) > 0 && !slices.ContainsFunc(movie.Genre, func(g models.Genre) bool {
+		return slices.Contains(query.GenreNames, g.GenreName)
+	}) {
+		return false
+	}
+
+	if len(query.GenreIDs) > 0 && !slices.ContainsFunc(movie.Genre, func(g models.Genre) bool {
+		return slices.Contains(query.GenreIDs, g.GenreID)
+	}) {
+		return false
+	}
+
+	if query.RankingName != "" && movie.Ranking.RankingName != query.RankingName {
+		return false
+	}
+
+	if query.MinRanking != nil && movie.Ranking.RankingValue < *query.MinRanking {
+		return false
+	}
+
+	if query.MaxRanking != nil && movie.Ranking.RankingValue > *query.MaxRanking {
+		return false
+	}
+
+	if query.TitlePrefix != "" && !strings.HasPrefix(strings.ToLower(movie.Title), strings.ToLower(query.TitlePrefix)) {
+		return false
+	}
+
+	if query.ImdbIDs != nil && !slices.Contains(query.ImdbIDs, movie.ImdbID) {
+		return false
+	}
+
+	if slices.Contains(query.ExcludeImdbIDs, movie.ImdbID) {
+		return false
+	}
+
+	if query.WithAdminReview && movie.AdminReview == "" {
+		return false
+	}
+
+	if query.ExcludeRankingStatus != "" && movie.RankingStatus == query.ExcludeRankingStatus {
+		return false
+	}
+
+	return true
+}
+
+// compareMovies orders movies by the sort field of query, then by _id
+func compareMovies(a, b models.Movie, query MovieQuery) int {
+	var order int
+
+	if query.SortField == SortByRanking {
+		order = a.Ranking.RankingValue - b.Ranking.RankingValue
+	} else {
+		order = strings.Compare(a.Title, b.Title)
+	}
+
+	if query.Descending {
+		order = -order
+	}
+
+	if order != 0 {
+		return order
+	}
+
+	return strings.Compare(a.ID.Hex(), b.ID.Hex())
+}
+
+func (r *MemoryMovieRepository) List(ctx context.Context, query MovieQuery) ([]models.Movie, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	movies := []models.Movie{}
+
+	var after *models.Movie
+
+	if query.After != nil {
+		after = &models.Movie{
+			ID:      query.After.ID,
+			Title:   query.After.Title,
+			Ranking: models.Ranking{RankingValue: query.After.Ranking},
+		}
+	}
+
+	for _, movie := range r.movies {
+		if !matchesMovie(movie, query) {
+			continue
+		}
+
+		if after != nil && compareMovies(movie, *after, query) <= 0 {
+			continue
+		}
+
+		movies = append(movies, cloneMovie(movie))
+	}
+
+	sort.Slice(movies, func(i, j int) bool { return compareMovies(movies[i], movies[j], query) < 0 })
+
+	if query.Skip >= int64(len(movies)) {
+		return []models.Movie{}, nil
+	}
+
+	movies = movies[query.Skip:]
+
+	if query.Limit > 0 && int64(len(movies)) > query.Limit {
+		movies = movies[:query.Limit]
+	}
+
+	return movies, nil
+}
+
+func (r *MemoryMovieRepository) Count(ctx context.Context, query MovieQuery) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+
+	for _, movie := range r.movies {
+		if matchesMovie(movie, query) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *MemoryMovieRepository) Get(ctx context.Context, imdbId string) (models.Movie, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	movie, ok := r.movies[imdbId]
+
+	if !ok {
+		return models.Movie{}, ErrNotFound
+	}
+
+	return cloneMovie(movie), nil
+}
+
+func (r *MemoryMovieRepository) Insert(ctx context.Context, movie *models.Movie) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.movies[movie.ImdbID]; ok {
+		return ErrDuplicate
+	}
+
+	movie.ID = bson.NewObjectID()
+	r.movies[movie.ImdbID] = cloneMovie(*movie)
+
+	return nil
+}
+
+func (r *MemoryMovieRepository) Update(ctx context.Context, imdbId string, patch models.MoviePatch) (models.Movie, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	movie, ok := r.movies[imdbId]
+
+	if !ok {
+		return models.Movie{}, ErrNotFound
+	}
+
+	if patch.ImdbID != nil && *patch.ImdbID != imdbId {
+		if _, taken := r.movies[*patch.ImdbID]; taken {
+			return models.Movie{}, ErrDuplicate
+		}
+		movie.ImdbID = *patch.ImdbID
+	}
+	if patch.Title != nil {
+		movie.Title = *patch.Title
+	}
+	if patch.PosterPath != nil {
+		movie.PosterPath = *patch.PosterPath
+	}
+	if patch.YouTubeID != nil {
+		movie.YouTubeID = *patch.YouTubeID
+	}
+	if patch.Genre != nil {
+		movie.Genre = slices.Clone(*patch.Genre)
+	}
+
+	delete(r.movies, imdbId)
+	r.movies[movie.ImdbID] = movie
+
+	return cloneMovie(movie), nil
+}
+
+func (r *MemoryMovieRepository) Delete(ctx context.Context, imdbId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.movies[imdbId]; !ok {
+		return ErrNotFound
+	}
+
+	delete(r.movies, imdbId)
+
+	return nil
+}
+
+// updateMovie applies change to the movie with imdbId, returning ErrNotFound when there is none
+func (r *MemoryMovieRepository) updateMovie(imdbId string, change func(movie *models.Movie)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	movie, ok := r.movies[imdbId]
+
+	if !ok {
+		return ErrNotFound
+	}
+
+	change(&movie)
+	r.movies[imdbId] = movie
+
+	return nil
+}
+
+// updateMovies applies change to every movie for which match returns true
+func (r *MemoryMovieRepository) updateMovies(match func(movie models.Movie) bool, change func(movie *models.Movie)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for imdbId, movie := range r.movies {
+		if match(movie) {
+			movie.Genre = slices.Clone(movie.Genre)
+			change(&movie)
+			r.movies[imdbId] = movie
+		}
+	}
+}
+
+// countMovies counts the movies for which match returns true
+func (r *MemoryMovieRepository) countMovies(match func(movie models.Movie) bool) int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+
+	for _, movie := range r.movies {
+		if match(movie) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (r *MemoryMovieRepository) SetAdminReview(ctx context.Context, imdbId, review, jobId string) error {
+	return r.updateMovie(imdbId, func(movie *models.Movie) {
+		movie.AdminReview = review
+		movie.RankingStatus = models.JobStatusPending
+		movie.RankingJobID = jobId
+	})
+}
+
+func (r *MemoryMovieRepository) SetRankingResult(ctx context.Context, imdbId, jobId, status string, ranking *models.Ranking) error {
+	r.updateMovies(
+		func(movie models.Movie) bool { return movie.ImdbID == imdbId && movie.RankingJobID == jobId },
+		func(movie *models.Movie) {
+			movie.RankingStatus = status
+			if ranking != nil {
+				movie.Ranking = *ranking
+			}
+		},
+	)
+
+	return nil
+}
+
+func (r *MemoryMovieRepository) SetRerankResult(ctx context.Context, imdbId, review string, ranking models.Ranking) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	movie, ok := r.movies[imdbId]
+
+	if !ok || movie.AdminReview != review || movie.RankingStatus == models.JobStatusPending {
+		return ErrNotFound
+	}
+
+	movie.Ranking = ranking
+	movie.RankingStatus = models.JobStatusSucceeded
+	r.movies[imdbId] = movie
+
+	return nil
+}
+
+func (r *MemoryMovieRepository) SetRating(ctx context.Context, imdbId string, average float64, count int) error {
+	return r.updateMovie(imdbId, func(movie *models.Movie) {
+		movie.AverageRating = average
+		movie.RatingCount = count
+	})
+}
+
+func (r *MemoryMovieRepository) ReplaceRanking(ctx context.Context, previous, current models.Ranking) error {
+	r.updateMovies(
+		func(movie models.Movie) bool {
+			return movie.Ranking.RankingValue == previous.RankingValue && movie.Ranking.RankingName == previous.RankingName
+		},
+		func(movie *models.Movie) { movie.Ranking = current },
+	)
+
+	return nil
+}
+
+func (r *MemoryMovieRepository) SetRankingValues(ctx context.Context, values map[string]int) error {
+	r.updateMovies(
+		func(movie models.Movie) bool {
+			_, ok := values[movie.Ranking.RankingName]
+			return ok
+		},
+		func(movie *models.Movie) { movie.Ranking.RankingValue = values[movie.Ranking.RankingName] },
+	)
+
+	return nil
+}
+
+func (r *MemoryMovieRepository) CountWithRanking(ctx context.Context, rankingValue int) (int64, error) {
+	return r.countMovies(func(movie models.Movie) bool { return movie.Ranking.RankingValue == rankingValue }), nil
+}
+
+func (r *MemoryMovieRepository) CountWithGenre(ctx context.Context, genreId int) (int64, error) {
+	return r.countMovies(func(movie models.Movie) bool { return hasGenre(movie.Genre, genreId) }), nil
+}
+
+func (r *MemoryMovieRepository) RenameGenre(ctx context.Context, genre models.Genre) error {
+	r.updateMovies(
+		func(movie models.Movie) bool { return hasGenre(movie.Genre, genre.GenreID) },
+		func(movie *models.Movie) { renameGenre(movie.Genre, genre) },
+	)
+
+	return nil
+}
+
+// Text search weights, matching the movie_text_search index
+const (
+	titleWeight       = 10
+	adminReviewWeight = 2
+)
+
+// words splits s into lower-cased words
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// TextSearch scores movies by how many query words their title and admin
+// review contain; unlike MongoDB it does not stem words.
+func (r *MemoryMovieRepository) TextSearch(ctx context.Context, query string, limit int64) ([]models.MovieSearchResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	terms := words(query)
+	results := []models.MovieSearchResult{}
+
+	for _, movie := range r.movies {
+		title, review := words(movie.Title), words(movie.AdminReview)
+		score := 0.0
+
+		for _, term := range terms {
+			if slices.Contains(title, term) {
+				score += titleWeight
+			}
+			if slices.Contains(review, term) {
+				score += adminReviewWeight
+			}
+		}
+
+		if score > 0 {
+			results = append(results, models.MovieSearchResult{Movie: cloneMovie(movie), Score: score})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Movie.ImdbID < results[j].Movie.ImdbID
+	})
+
+	if int64(len(results)) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+func (r *MemoryMovieRepository) ListByTitleWordPrefix(ctx context.Context, prefixes []string, limit int64) ([]models.Movie, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	movies := []models.Movie{}
+
+	for _, movie := range r.movies {
+		if int64(len(movies)) >= limit {
+			break
+		}
+
+		if slices.ContainsFunc(words(movie.Title), func(word string) bool {
+			return slices.ContainsFunc(prefixes, func(prefix string) bool {
+				return strings.HasPrefix(word, strings.ToLower(prefix))
+			})
+		}) {
+			movies = append(movies, cloneMovie(movie))
+		}
+	}
+
+	return movies, nil
+}
+
+// hasGenre reports whether genres contains the genre with genreId
+func hasGenre(genres []models.Genre, genreId int) bool {
+	return slices.ContainsFunc(genres, func(g models.Genre) bool { return g.GenreID == genreId })
+}
+
+// renameGenre renames the copies of genre in genres
+func renameGenre(genres []models.Genre, genre models.Genre) {
+	for i := range genres {
+		if genres[i].GenreID == genre.GenreID {
+			genres[i].GenreName = genre.GenreName
+		}
+	}
+}
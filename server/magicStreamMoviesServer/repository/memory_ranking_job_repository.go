@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// MemoryRankingJobRepository keeps ranking jobs in memory. It is safe for concurrent use.
+type MemoryRankingJobRepository struct {
+	mu   sync.Mutex
+	jobs map[bson.ObjectID]models.RankingJob
+}
+
+// NewMemoryRankingJobRepository creates an empty in-memory RankingJobRepository
+func NewMemoryRankingJobRepository() *MemoryRankingJobRepository {
+	return &MemoryRankingJobRepository{jobs: map[bson.ObjectID]models.RankingJob{}}
+}
+
+func (r *MemoryRankingJobRepository) Insert(ctx context.Context, job models.RankingJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.jobs[job.ID]; ok {
+		return ErrDuplicate
+	}
+
+	r.jobs[job.ID] = job
+
+	return nil
+}
+
+func (r *MemoryRankingJobRepository) Get(ctx context.Context, id bson.ObjectID) (models.RankingJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[id]
+
+	if !ok {
+		return models.RankingJob{}, ErrNotFound
+	}
+
+	return job, nil
+}
+
+func (r *MemoryRankingJobRepository) Claim(ctx context.Context, id bson.ObjectID, now, leaseUntil time.Time) (models.RankingJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[id]
+
+	if !ok || job.Status != models.JobStatusPending || job.NextAttemptAt.After(now) {
+		return models.RankingJob{}, ErrNotFound
+	}
+
+	job.Status = models.JobStatusRunning
+	job.LeaseExpiresAt = &leaseUntil
+	job.Attempts++
+	job.UpdatedAt = now
+	r.jobs[id] = job
+
+	return job, nil
+}
+
+func (r *MemoryRankingJobRepository) Finish(ctx context.Context, id bson.ObjectID, attempt int, outcome JobOutcome) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[id]
+
+	if !ok || job.Status != models.JobStatusRunning || job.Attempts != attempt {
+		return ErrNotFound
+	}
+
+	job.Status = outcome.Status
+	job.LeaseExpiresAt = nil
+	job.UpdatedAt = time.Now()
+
+	if outcome.Ranking != nil {
+		ranking := *outcome.Ranking
+		job.Ranking = &ranking
+	}
+	if outcome.LastError != nil {
+		job.LastError = *outcome.LastError
+	}
+	if outcome.NextAttemptAt != nil {
+		job.NextAttemptAt = *outcome.NextAttemptAt
+	}
+	if outcome.Attempts != nil {
+		job.Attempts = *outcome.Attempts
+	}
+
+	r.jobs[id] = job
+
+	return nil
+}
+
+func (r *MemoryRankingJobRepository) Due(ctx context.Context, now time.Time, limit int64) ([]bson.ObjectID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := []models.RankingJob{}
+
+	for _, job := range r.jobs {
+		if job.Status == models.JobStatusPending && !job.NextAttemptAt.After(now) {
+			due = append(due, job)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+
+	if limit > 0 && int64(len(due)) > limit {
+		due = due[:limit]
+	}
+
+	ids := make([]bson.ObjectID, 0, len(due))
+
+	for _, job := range due {
+		ids = append(ids, job.ID)
+	}
+
+	return ids, nil
+}
+
+func (r *MemoryRankingJobRepository) ReleaseExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var released int64
+
+	for id, job := range r.jobs {
+		if job.Status != models.JobStatusRunning || job.LeaseExpiresAt == nil || !job.LeaseExpiresAt.Before(now) {
+			continue
+		}
+
+		job.Status = models.JobStatusPending
+		job.LeaseExpiresAt = nil
+		job.NextAttemptAt = now
+		job.UpdatedAt = now
+		r.jobs[id] = job
+		released++
+	}
+
+	return released, nil
+}
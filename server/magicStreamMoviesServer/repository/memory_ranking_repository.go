@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+// MemoryRankingRepository keeps the ranking scale in memory. It is safe for concurrent use.
+type MemoryRankingRepository struct {
+	mu       sync.RWMutex
+	rankings []models.Ranking
+}
+
+// NewMemoryRankingRepository creates an in-memory RankingRepository holding rankings
+func NewMemoryRankingRepository(rankings ...models.Ranking) *MemoryRankingRepository {
+	return &MemoryRankingRepository{rankings: append([]models.Ranking{}, rankings...)}
+}
+
+// indexOf returns the position of the ranking matching match, or -1; the caller holds the lock
+func (r *MemoryRankingRepository) indexOf(match func(ranking models.Ranking) bool) int {
+	for i, ranking := range r.rankings {
+		if match(ranking) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// conflicts reports whether ranking shares a value or name with a stored
+// ranking other than the one at position skip; the caller holds the lock
+func (r *MemoryRankingRepository) conflicts(ranking models.Ranking, skip int) bool {
+	for i, existing := range r.rankings {
+		if i != skip && (existing.RankingValue == ranking.RankingValue || existing.RankingName == ranking.RankingName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *MemoryRankingRepository) List(ctx context.Context) ([]models.Ranking, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rankings := append([]models.Ranking{}, r.rankings...)
+
+	sort.Slice(rankings, func(i, j int) bool { return rankings[i].RankingValue < rankings[j].RankingValue })
+
+	return rankings, nil
+}
+
+func (r *MemoryRankingRepository) Exists(ctx context.Context, ranking models.Ranking) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.indexOf(func(existing models.Ranking) bool {
+		return existing.RankingValue == ranking.RankingValue && existing.RankingName == ranking.RankingName
+	}) >= 0, nil
+}
+
+func (r *MemoryRankingRepository) Insert(ctx context.Context, ranking models.Ranking) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conflicts(ranking, -1) {
+		return ErrDuplicate
+	}
+
+	r.rankings = append(r.rankings, ranking)
+
+	return nil
+}
+
+func (r *MemoryRankingRepository) Replace(ctx context.Context, value int, ranking models.Ranking) (models.Ranking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(func(existing models.Ranking) bool { return existing.RankingValue == value })
+
+	if i < 0 {
+		return models.Ranking{}, ErrNotFound
+	}
+
+	if r.conflicts(ranking, i) {
+		return models.Ranking{}, ErrDuplicate
+	}
+
+	previous := r.rankings[i]
+	r.rankings[i] = ranking
+
+	return previous, nil
+}
+
+func (r *MemoryRankingRepository) Reorder(ctx context.Context, order []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reordered := append([]models.Ranking{}, r.rankings...)
+
+	for value, name := range order {
+		if i := r.indexOf(func(existing models.Ranking) bool { return existing.RankingName == name }); i >= 0 {
+			reordered[i].RankingValue = value + 1
+		}
+	}
+
+	values := map[int]bool{}
+
+	for _, ranking := range reordered {
+		if values[ranking.RankingValue] {
+			return ErrDuplicate
+		}
+		values[ranking.RankingValue] = true
+	}
+
+	r.rankings = reordered
+
+	return nil
+}
+
+func (r *MemoryRankingRepository) Delete(ctx context.Context, value int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(func(existing models.Ranking) bool { return existing.RankingValue == value })
+
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	r.rankings = append(r.rankings[:i], r.rankings[i+1:]...)
+
+	return nil
+}
+
+func (r *MemoryRankingRepository) ExcludeFromAI(ctx context.Context, value int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.rankings {
+		if r.rankings[i].RankingValue == value {
+			r.rankings[i].ExcludedFromAI = true
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// MemoryRerankRunRepository keeps re-rank runs with their diffs and failures in memory. It is safe for concurrent use.
+type MemoryRerankRunRepository struct {
+	mu       sync.RWMutex
+	runs     map[bson.ObjectID]models.RerankRun
+	diffs    map[bson.ObjectID][]models.RankingDiff
+	failures map[bson.ObjectID][]models.RerankFailure
+}
+
+// NewMemoryRerankRunRepository creates an empty in-memory RerankRunRepository
+func NewMemoryRerankRunRepository() *MemoryRerankRunRepository {
+	return &MemoryRerankRunRepository{
+		runs:     map[bson.ObjectID]models.RerankRun{},
+		diffs:    map[bson.ObjectID][]models.RankingDiff{},
+		failures: map[bson.ObjectID][]models.RerankFailure{},
+	}
+}
+
+func (r *MemoryRerankRunRepository) Insert(ctx context.Context, run models.RerankRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.runs[run.ID]; ok {
+		return ErrDuplicate
+	}
+
+	run.Diffs = nil
+	run.Failures = nil
+	r.runs[run.ID] = run
+
+	return nil
+}
+
+func (r *MemoryRerankRunRepository) Get(ctx context.Context, id bson.ObjectID) (models.RerankRun, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	run, ok := r.runs[id]
+
+	if !ok {
+		return models.RerankRun{}, ErrNotFound
+	}
+
+	return run, nil
+}
+
+func (r *MemoryRerankRunRepository) Save(ctx context.Context, run models.RerankRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.runs[run.ID]; ok {
+		run.Diffs = nil
+		run.Failures = nil
+		r.runs[run.ID] = run
+	}
+
+	return nil
+}
+
+func (r *MemoryRerankRunRepository) AddDiff(ctx context.Context, diff models.RankingDiff) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.diffs[diff.RunID] = append(r.diffs[diff.RunID], diff)
+
+	return nil
+}
+
+func (r *MemoryRerankRunRepository) AddFailure(ctx context.Context, failure models.RerankFailure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failures[failure.RunID] = append(r.failures[failure.RunID], failure)
+
+	return nil
+}
+
+func (r *MemoryRerankRunRepository) Diffs(ctx context.Context, runId bson.ObjectID, skip, limit int64) ([]models.RankingDiff, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(page(r.diffs[runId], skip, limit)), nil
+}
+
+func (r *MemoryRerankRunRepository) Failures(ctx context.Context, runId bson.ObjectID, skip, limit int64) ([]models.RerankFailure, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(page(r.failures[runId], skip, limit)), nil
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// MemoryReviewRepository keeps user reviews in memory. It is safe for concurrent use.
+type MemoryReviewRepository struct {
+	mu      sync.RWMutex
+	reviews []models.UserReview
+}
+
+// NewMemoryReviewRepository creates an empty in-memory ReviewRepository
+func NewMemoryReviewRepository() *MemoryReviewRepository {
+	return &MemoryReviewRepository{}
+}
+
+// filterReviews returns the reviews for which match returns true; the caller holds the lock
+func (r *MemoryReviewRepository) filterReviews(match func(review models.UserReview) bool) []models.UserReview {
+	reviews := []models.UserReview{}
+
+	for _, review := range r.reviews {
+		if match(review) {
+			reviews = append(reviews, review)
+		}
+	}
+
+	return reviews
+}
+
+// indexOf returns the position of the user's review of the movie, or -1; the caller holds the lock
+func (r *MemoryReviewRepository) indexOf(imdbId, userId string) int {
+	return slices.IndexFunc(r.reviews, func(review models.UserReview) bool {
+		return review.ImdbID == imdbId && review.UserID == userId
+	})
+}
+
+func (r *MemoryReviewRepository) Insert(ctx context.Context, review *models.UserReview) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.indexOf(review.ImdbID, review.UserID) >= 0 {
+		return ErrDuplicate
+	}
+
+	review.ID = bson.NewObjectID()
+	r.reviews = append(r.reviews, *review)
+
+	return nil
+}
+
+func (r *MemoryReviewRepository) Update(ctx context.Context, imdbId, userId string, input models.UserReviewInput) (models.UserReview, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(imdbId, userId)
+
+	if i < 0 {
+		return models.UserReview{}, ErrNotFound
+	}
+
+	r.reviews[i].Rating = input.Rating
+	r.reviews[i].Text = input.Text
+	r.reviews[i].UpdatedAt = time.Now()
+
+	return r.reviews[i], nil
+}
+
+func (r *MemoryReviewRepository) Delete(ctx context.Context, imdbId, userId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(imdbId, userId)
+
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	r.reviews = slices.Delete(r.reviews, i, i+1)
+
+	return nil
+}
+
+func (r *MemoryReviewRepository) ListByMovie(ctx context.Context, imdbId string, skip, limit int64) ([]models.UserReview, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reviews := r.filterReviews(func(review models.UserReview) bool { return review.ImdbID == imdbId })
+
+	sort.Slice(reviews, func(i, j int) bool {
+		if !reviews[i].CreatedAt.Equal(reviews[j].CreatedAt) {
+			return reviews[i].CreatedAt.After(reviews[j].CreatedAt)
+		}
+		return reviews[i].ID.Hex() > reviews[j].ID.Hex()
+	})
+
+	return page(reviews, skip, limit), nil
+}
+
+func (r *MemoryReviewRepository) CountByMovie(ctx context.Context, imdbId string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.filterReviews(func(review models.UserReview) bool { return review.ImdbID == imdbId }))), nil
+}
+
+func (r *MemoryReviewRepository) ListByUser(ctx context.Context, userId string) ([]models.UserReview, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.filterReviews(func(review models.UserReview) bool { return review.UserID == userId }), nil
+}
+
+func (r *MemoryReviewRepository) Rating(ctx context.Context, imdbId string) (Rating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reviews := r.filterReviews(func(review models.UserReview) bool { return review.ImdbID == imdbId })
+
+	if len(reviews) == 0 {
+		return Rating{}, nil
+	}
+
+	total := 0
+
+	for _, review := range reviews {
+		total += review.Rating
+	}
+
+	average := float64(total) / float64(len(reviews))
+
+	return Rating{Average: math.Round(average*100) / 100, Count: len(reviews)}, nil
+}
+
+func (r *MemoryReviewRepository) Users(ctx context.Context) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]string, 0, len(r.reviews))
+
+	for _, review := range r.reviews {
+		users = append(users, review.UserID)
+	}
+
+	return distinct(users), nil
+}
+
+func (r *MemoryReviewRepository) MoveMovie(ctx context.Context, fromImdbId, toImdbId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.reviews {
+		if r.reviews[i].ImdbID == fromImdbId {
+			r.reviews[i].ImdbID = toImdbId
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryReviewRepository) DeleteMovie(ctx context.Context, imdbId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reviews = slices.DeleteFunc(r.reviews, func(review models.UserReview) bool { return review.ImdbID == imdbId })
+
+	return nil
+}
+
+// page returns the items left after skipping skip, at most limit of them
+func page[T any](items []T, skip, limit int64) []T {
+	if skip >= int64(len(items)) {
+		return []T{}
+	}
+
+	items = items[skip:]
+
+	if limit > 0 && int64(len(items)) > limit {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+// distinct returns values sorted and without duplicates
+func distinct(values []string) []string {
+	slices.Sort(values)
+	return slices.Compact(values)
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+)
+
+// MemorySimilarityRepository keeps the item-item movie similarities in memory. It is safe for concurrent use.
+type MemorySimilarityRepository struct {
+	mu           sync.RWMutex
+	similarities map[string]models.MovieSimilarity
+}
+
+// NewMemorySimilarityRepository creates an empty in-memory SimilarityRepository
+func NewMemorySimilarityRepository() *MemorySimilarityRepository {
+	return &MemorySimilarityRepository{similarities: map[string]models.MovieSimilarity{}}
+}
+
+// removeNeighbour drops imdbId from every neighbour list; the caller holds the lock
+func (r *MemorySimilarityRepository) removeNeighbour(imdbId string) {
+	for id, similarity := range r.similarities {
+		similarity.Neighbours = slices.DeleteFunc(slices.Clone(similarity.Neighbours), func(n models.Neighbour) bool { return n.ImdbID == imdbId })
+		r.similarities[id] = similarity
+	}
+}
+
+func (r *MemorySimilarityRepository) Count(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.similarities)), nil
+}
+
+func (r *MemorySimilarityRepository) ListByMovies(ctx context.Context, imdbIds []string) ([]models.MovieSimilarity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	similarities := []models.MovieSimilarity{}
+
+	for _, imdbId := range imdbIds {
+		if similarity, ok := r.similarities[imdbId]; ok {
+			similarity.Neighbours = slices.Clone(similarity.Neighbours)
+			similarities = append(similarities, similarity)
+		}
+	}
+
+	return similarities, nil
+}
+
+func (r *MemorySimilarityRepository) Save(ctx context.Context, imdbId string, neighbours []models.Neighbour) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(neighbours) == 0 {
+		delete(r.similarities, imdbId)
+		return nil
+	}
+
+	r.similarities[imdbId] = models.MovieSimilarity{ImdbID: imdbId, Neighbours: slices.Clone(neighbours), UpdatedAt: time.Now()}
+
+	return nil
+}
+
+func (r *MemorySimilarityRepository) ReplaceNeighbour(ctx context.Context, imdbId string, scores map[string]float64, keep int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.removeNeighbour(imdbId)
+
+	for other, score := range scores {
+		similarity := r.similarities[other]
+		similarity.ImdbID = other
+		similarity.Neighbours = append(similarity.Neighbours, models.Neighbour{ImdbID: imdbId, Score: score})
+
+		sort.SliceStable(similarity.Neighbours, func(i, j int) bool { return similarity.Neighbours[i].Score > similarity.Neighbours[j].Score })
+
+		if len(similarity.Neighbours) > keep {
+			similarity.Neighbours = similarity.Neighbours[:keep]
+		}
+
+		similarity.UpdatedAt = time.Now()
+		r.similarities[other] = similarity
+	}
+
+	return nil
+}
+
+func (r *MemorySimilarityRepository) MoveMovie(ctx context.Context, fromImdbId, toImdbId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if similarity, ok := r.similarities[fromImdbId]; ok {
+		delete(r.similarities, fromImdbId)
+		similarity.ImdbID = toImdbId
+		r.similarities[toImdbId] = similarity
+	}
+
+	for id, similarity := range r.similarities {
+		similarity.Neighbours = slices.Clone(similarity.Neighbours)
+
+		for i := range similarity.Neighbours {
+			if similarity.Neighbours[i].ImdbID == fromImdbId {
+				similarity.Neighbours[i].ImdbID = toImdbId
+			}
+		}
+
+		r.similarities[id] = similarity
+	}
+
+	return nil
+}
+
+func (r *MemorySimilarityRepository) DeleteMovie(ctx context.Context, imdbId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.similarities, imdbId)
+	r.removeNeighbour(imdbId)
+
+	return nil
+}
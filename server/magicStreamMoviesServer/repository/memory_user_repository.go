@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// MemoryUserRepository keeps users in memory. It is safe for concurrent use.
+type MemoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[string]models.User
+}
+
+// NewMemoryUserRepository creates an empty in-memory UserRepository
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{users: map[string]models.User{}}
+}
+
+// cloneUser copies the slices of user so callers cannot modify the stored user
+func cloneUser(user models.User) models.User {
+	user.FavouriteGenres = slices.Clone(user.FavouriteGenres)
+	return user
+}
+
+func (r *MemoryUserRepository) Insert(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.users {
+		if existing.Email == user.Email || existing.UserID == user.UserID {
+			return ErrDuplicate
+		}
+	}
+
+	user.ID = bson.NewObjectID()
+	r.users[user.UserID] = cloneUser(*user)
+
+	return nil
+}
+
+func (r *MemoryUserRepository) Get(ctx context.Context, userId string) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[userId]
+
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+
+	return cloneUser(user), nil
+}
+
+// findByEmail returns the user id registered with email; the caller holds the lock
+func (r *MemoryUserRepository) findByEmail(email string) (string, bool) {
+	for userId, user := range r.users {
+		if user.Email == email {
+			return userId, true
+		}
+	}
+
+	return "", false
+}
+
+func (r *MemoryUserRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	userId, ok := r.findByEmail(email)
+
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+
+	return cloneUser(r.users[userId]), nil
+}
+
+func (r *MemoryUserRepository) List(ctx context.Context, query UserQuery) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := []models.User{}
+
+	for _, user := range r.users {
+		if query.Role == "" || user.Role == query.Role {
+			users = append(users, cloneUser(user))
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool { return users[i].CreatedAt.Before(users[j].CreatedAt) })
+
+	if query.Skip >= int64(len(users)) {
+		return []models.User{}, nil
+	}
+
+	users = users[query.Skip:]
+
+	if query.Limit > 0 && int64(len(users)) > query.Limit {
+		users = users[:query.Limit]
+	}
+
+	return users, nil
+}
+
+func (r *MemoryUserRepository) Count(ctx context.Context, query UserQuery) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+
+	for _, user := range r.users {
+		if query.Role == "" || user.Role == query.Role {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// update applies update to the stored user with userId; the caller holds the lock
+func (r *MemoryUserRepository) update(userId string, update UserUpdate) models.User {
+	user := r.users[userId]
+
+	if update.Role != nil {
+		user.Role = *update.Role
+	}
+
+	if update.Disabled != nil {
+		user.Disabled = *update.Disabled
+	}
+
+	user.UpdatedAt = time.Now()
+	r.users[userId] = user
+
+	return cloneUser(user)
+}
+
+func (r *MemoryUserRepository) Update(ctx context.Context, userId string, update UserUpdate) (models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[userId]; !ok {
+		return models.User{}, ErrNotFound
+	}
+
+	return r.update(userId, update), nil
+}
+
+func (r *MemoryUserRepository) UpdateByEmail(ctx context.Context, email string, update UserUpdate) (models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	userId, ok := r.findByEmail(email)
+
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+
+	return r.update(userId, update), nil
+}
+
+func (r *MemoryUserRepository) Delete(ctx context.Context, userId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[userId]; !ok {
+		return ErrNotFound
+	}
+
+	delete(r.users, userId)
+
+	return nil
+}
+
+func (r *MemoryUserRepository) CountWithGenre(ctx context.Context, genreId int) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+
+	for _, user := range r.users {
+		if hasGenre(user.FavouriteGenres, genreId) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *MemoryUserRepository) RenameGenre(ctx context.Context, genre models.Genre) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for userId, user := range r.users {
+		if hasGenre(user.FavouriteGenres, genre.GenreID) {
+			user = cloneUser(user)
+			renameGenre(user.FavouriteGenres, genre)
+			r.users[userId] = user
+		}
+	}
+
+	return nil
+}
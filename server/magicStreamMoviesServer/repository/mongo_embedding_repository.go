@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoEmbeddingRepository stores movie embeddings in a MongoDB collection
+type MongoEmbeddingRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoEmbeddingRepository creates an EmbeddingRepository backed by collection
+func NewMongoEmbeddingRepository(collection *mongo.Collection) *MongoEmbeddingRepository {
+	return &MongoEmbeddingRepository{collection: collection}
+}
+
+func (r *MongoEmbeddingRepository) Get(ctx context.Context, imdbId, model string) (models.MovieEmbedding, error) {
+	var embedding models.MovieEmbedding
+
+	err := r.collection.FindOne(ctx, bson.M{"imdb_id": imdbId, "model": model}).Decode(&embedding)
+
+	return embedding, mongoError(err)
+}
+
+func (r *MongoEmbeddingRepository) ListByModel(ctx context.Context, model string) ([]models.MovieEmbedding, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"model": model})
+
+	if err != nil {
+		return nil, err
+	}
+
+	embeddings := []models.MovieEmbedding{}
+
+	if err := cursor.All(ctx, &embeddings); err != nil {
+		return nil, err
+	}
+
+	return embeddings, nil
+}
+
+func (r *MongoEmbeddingRepository) Hashes(ctx context.Context, model string) (map[string]string, error) {
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"model": model},
+		options.Find().SetProjection(bson.M{"imdb_id": 1, "text_hash": 1}),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	hashes := map[string]string{}
+
+	for cursor.Next(ctx) {
+		var embedding models.MovieEmbedding
+
+		if err := cursor.Decode(&embedding); err != nil {
+			return nil, err
+		}
+
+		hashes[embedding.ImdbID] = embedding.TextHash
+	}
+
+	return hashes, cursor.Err()
+}
+
+func (r *MongoEmbeddingRepository) Save(ctx context.Context, embeddings []models.MovieEmbedding) error {
+	if len(embeddings) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(embeddings))
+
+	for _, embedding := range embeddings {
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"imdb_id": embedding.ImdbID, "model": embedding.Model}).
+			SetReplacement(embedding).
+			SetUpsert(true))
+	}
+
+	_, err := r.collection.BulkWrite(ctx, writes)
+
+	return err
+}
+
+func (r *MongoEmbeddingRepository) DeleteMovies(ctx context.Context, imdbIds []string) error {
+	if len(imdbIds) == 0 {
+		return nil
+	}
+
+	_, err := r.collection.DeleteMany(ctx, bson.M{"imdb_id": bson.M{"$in": imdbIds}})
+
+	return err
+}
+
+func (r *MongoEmbeddingRepository) DeleteOtherModels(ctx context.Context, model string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"model": bson.M{"$ne": model}})
+
+	return err
+}
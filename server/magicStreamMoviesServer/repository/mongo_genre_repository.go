@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoGenreRepository stores genres in a MongoDB collection
+type MongoGenreRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoGenreRepository creates a GenreRepository backed by collection
+func NewMongoGenreRepository(collection *mongo.Collection) *MongoGenreRepository {
+	return &MongoGenreRepository{collection: collection}
+}
+
+// genreDocument is how a genre is stored; name_key backs the name_key_unique index
+func genreDocument(genre models.Genre) bson.M {
+	return bson.M{
+		"genre_id":   genre.GenreID,
+		"genre_name": genre.GenreName,
+		"name_key":   GenreNameKey(genre.GenreName),
+	}
+}
+
+// findGenres returns the genres matching filter in the order of opts
+func (r *MongoGenreRepository) findGenres(ctx context.Context, filter bson.M, opts ...options.Lister[options.FindOptions]) ([]models.Genre, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	genres := []models.Genre{}
+
+	if err := cursor.All(ctx, &genres); err != nil {
+		return nil, err
+	}
+
+	return genres, nil
+}
+
+func (r *MongoGenreRepository) List(ctx context.Context) ([]models.Genre, error) {
+	return r.findGenres(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "genre_name", Value: 1}}))
+}
+
+func (r *MongoGenreRepository) GetMany(ctx context.Context, ids []int) ([]models.Genre, error) {
+	return r.findGenres(ctx, bson.M{"genre_id": bson.M{"$in": ids}})
+}
+
+func (r *MongoGenreRepository) Insert(ctx context.Context, genre models.Genre) error {
+	_, err := r.collection.InsertOne(ctx, genreDocument(genre))
+
+	return mongoError(err)
+}
+
+func (r *MongoGenreRepository) NextID(ctx context.Context) (int, error) {
+	var last models.Genre
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "genre_id", Value: -1}})
+
+	err := r.collection.FindOne(ctx, bson.M{}, opts).Decode(&last)
+
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 1, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return last.GenreID + 1, nil
+}
+
+func (r *MongoGenreRepository) Update(ctx context.Context, genre models.Genre) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"genre_id": genre.GenreID}, bson.M{"$set": genreDocument(genre)})
+
+	if err != nil {
+		return mongoError(err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoGenreRepository) Delete(ctx context.Context, genreId int) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"genre_id": genreId})
+
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
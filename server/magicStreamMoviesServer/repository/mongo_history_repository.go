@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoHistoryRepository stores users' watch history in a MongoDB collection
+type MongoHistoryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoHistoryRepository creates a HistoryRepository backed by collection
+func NewMongoHistoryRepository(collection *mongo.Collection) *MongoHistoryRepository {
+	return &MongoHistoryRepository{collection: collection}
+}
+
+// historyFilter translates the filters of query into a MongoDB filter
+func historyFilter(query HistoryQuery) bson.M {
+	filter := bson.M{"user_id": query.UserID}
+
+	if query.Unfinished {
+		filter["completed"] = false
+	}
+
+	if query.MinProgress > 0 {
+		filter["progress"] = bson.M{"$gte": query.MinProgress}
+	}
+
+	return filter
+}
+
+func (r *MongoHistoryRepository) Record(ctx context.Context, watched models.WatchProgress) (models.WatchProgress, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"position":   watched.Position,
+			"duration":   watched.Duration,
+			"progress":   watched.Progress,
+			"completed":  watched.Completed,
+			"updated_at": watched.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{"started_at": watched.UpdatedAt},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var recorded models.WatchProgress
+
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"user_id": watched.UserID, "imdb_id": watched.ImdbID}, update, opts).Decode(&recorded)
+
+	return recorded, mongoError(err)
+}
+
+func (r *MongoHistoryRepository) List(ctx context.Context, query HistoryQuery) ([]models.HistoryEntry, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: historyFilter(query)}},
+		{{Key: "$sort", Value: bson.D{{Key: "updated_at", Value: -1}, {Key: "_id", Value: -1}}}},
+		{{Key: "$skip", Value: query.Skip}},
+		{{Key: "$limit", Value: query.Limit}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "movies",
+			"localField":   "imdb_id",
+			"foreignField": "imdb_id",
+			"as":           "movie",
+		}}},
+		{{Key: "$unwind", Value: "$movie"}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []models.HistoryEntry{}
+
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *MongoHistoryRepository) Count(ctx context.Context, query HistoryQuery) (int64, error) {
+	return r.collection.CountDocuments(ctx, historyFilter(query))
+}
+
+func (r *MongoHistoryRepository) ListByUser(ctx context.Context, userId string) ([]models.WatchProgress, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userId})
+
+	if err != nil {
+		return nil, err
+	}
+
+	history := []models.WatchProgress{}
+
+	if err := cursor.All(ctx, &history); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (r *MongoHistoryRepository) Delete(ctx context.Context, userId, imdbId string) error {
+	return deleteOne(ctx, r.collection, bson.M{"user_id": userId, "imdb_id": imdbId})
+}
+
+func (r *MongoHistoryRepository) Users(ctx context.Context) ([]string, error) {
+	return distinctUsers(ctx, r.collection)
+}
+
+func (r *MongoHistoryRepository) MoveMovie(ctx context.Context, fromImdbId, toImdbId string) error {
+	return moveImdbId(ctx, r.collection, fromImdbId, toImdbId)
+}
+
+func (r *MongoHistoryRepository) DeleteMovie(ctx context.Context, imdbId string) error {
+	return deleteImdbId(ctx, r.collection, imdbId)
+}
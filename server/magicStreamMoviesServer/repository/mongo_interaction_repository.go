@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// MongoInteractionRepository stores the materialised user interactions in a MongoDB collection
+type MongoInteractionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoInteractionRepository creates an InteractionRepository backed by collection
+func NewMongoInteractionRepository(collection *mongo.Collection) *MongoInteractionRepository {
+	return &MongoInteractionRepository{collection: collection}
+}
+
+// findInteractions returns the interactions matching filter
+func (r *MongoInteractionRepository) findInteractions(ctx context.Context, filter bson.M) ([]models.Interaction, error) {
+	cursor, err := r.collection.Find(ctx, filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	interactions := []models.Interaction{}
+
+	if err := cursor.All(ctx, &interactions); err != nil {
+		return nil, err
+	}
+
+	return interactions, nil
+}
+
+func (r *MongoInteractionRepository) ListByUser(ctx context.Context, userId string) ([]models.Interaction, error) {
+	return r.findInteractions(ctx, bson.M{"user_id": userId})
+}
+
+func (r *MongoInteractionRepository) ListByMovie(ctx context.Context, imdbId string) ([]models.Interaction, error) {
+	return r.findInteractions(ctx, bson.M{"imdb_id": imdbId})
+}
+
+func (r *MongoInteractionRepository) ListByUsers(ctx context.Context, userIds []string) ([]models.Interaction, error) {
+	return r.findInteractions(ctx, bson.M{"user_id": bson.M{"$in": userIds}})
+}
+
+func (r *MongoInteractionRepository) Replace(ctx context.Context, userId string, weights map[string]float64) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userId}); err != nil {
+		return err
+	}
+
+	if len(weights) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]any, 0, len(weights))
+
+	for imdbId, weight := range weights {
+		docs = append(docs, models.Interaction{UserID: userId, ImdbID: imdbId, Weight: weight, UpdatedAt: now})
+	}
+
+	_, err := r.collection.InsertMany(ctx, docs)
+
+	return err
+}
+
+func (r *MongoInteractionRepository) Norms(ctx context.Context, imdbIds []string) (map[string]float64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"imdb_id": bson.M{"$in": imdbIds}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$imdb_id",
+			"squares": bson.M{"$sum": bson.M{"$multiply": bson.A{"$weight", "$weight"}}},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		ImdbID  string  `bson:"_id"`
+		Squares float64 `bson:"squares"`
+	}
+
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	norms := make(map[string]float64, len(rows))
+
+	for _, row := range rows {
+		norms[row.ImdbID] = math.Sqrt(row.Squares)
+	}
+
+	return norms, nil
+}
+
+func (r *MongoInteractionRepository) Users(ctx context.Context) ([]string, error) {
+	return distinctUsers(ctx, r.collection)
+}
+
+func (r *MongoInteractionRepository) MoveMovie(ctx context.Context, fromImdbId, toImdbId string) error {
+	return moveImdbId(ctx, r.collection, fromImdbId, toImdbId)
+}
+
+func (r *MongoInteractionRepository) DeleteMovie(ctx context.Context, imdbId string) error {
+	return deleteImdbId(ctx, r.collection, imdbId)
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoListRepository stores users' watchlists and favourites in a MongoDB collection
+type MongoListRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoListRepository creates a ListRepository backed by collection
+func NewMongoListRepository(collection *mongo.Collection) *MongoListRepository {
+	return &MongoListRepository{collection: collection}
+}
+
+// listFilter selects the items of the user's list, or of every list when list is empty
+func listFilter(userId, list string) bson.M {
+	filter := bson.M{"user_id": userId}
+
+	if list != "" {
+		filter["list"] = list
+	}
+
+	return filter
+}
+
+func (r *MongoListRepository) Entries(ctx context.Context, userId, list string) ([]models.ListEntry, error) {
+	sort := bson.D{{Key: "position", Value: 1}}
+
+	if list == models.ListFavourites {
+		sort = bson.D{{Key: "added_at", Value: -1}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: listFilter(userId, list)}},
+		{{Key: "$sort", Value: sort}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "movies",
+			"localField":   "imdb_id",
+			"foreignField": "imdb_id",
+			"as":           "movie",
+		}}},
+		// movies deleted since they were saved drop out of the list
+		{{Key: "$unwind", Value: "$movie"}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []models.ListEntry{}
+
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *MongoListRepository) Items(ctx context.Context, userId, list string) ([]models.ListItem, error) {
+	cursor, err := r.collection.Find(ctx, listFilter(userId, list))
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := []models.ListItem{}
+
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// nextPosition returns the position after the last item of a user's list
+func (r *MongoListRepository) nextPosition(ctx context.Context, userId, list string) (int, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "position", Value: -1}})
+
+	var last models.ListItem
+
+	err := r.collection.FindOne(ctx, listFilter(userId, list), opts).Decode(&last)
+
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 1, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return last.Position + 1, nil
+}
+
+func (r *MongoListRepository) Add(ctx context.Context, item *models.ListItem) error {
+	position, err := r.nextPosition(ctx, item.UserID, item.List)
+
+	if err != nil {
+		return err
+	}
+
+	item.ID = bson.NewObjectID()
+	item.Position = position
+
+	_, err = r.collection.InsertOne(ctx, item)
+
+	return mongoError(err)
+}
+
+func (r *MongoListRepository) Remove(ctx context.Context, userId, list, imdbId string) error {
+	return deleteOne(ctx, r.collection, bson.M{"user_id": userId, "list": list, "imdb_id": imdbId})
+}
+
+func (r *MongoListRepository) Reorder(ctx context.Context, userId, list string, order []string) error {
+	if len(order) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(order))
+
+	for i, imdbId := range order {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"user_id": userId, "list": list, "imdb_id": imdbId}).
+			SetUpdate(bson.M{"$set": bson.M{"position": i + 1}}))
+	}
+
+	_, err := r.collection.BulkWrite(ctx, writes)
+
+	return err
+}
+
+func (r *MongoListRepository) Users(ctx context.Context) ([]string, error) {
+	return distinctUsers(ctx, r.collection)
+}
+
+func (r *MongoListRepository) MoveMovie(ctx context.Context, fromImdbId, toImdbId string) error {
+	return moveImdbId(ctx, r.collection, fromImdbId, toImdbId)
+}
+
+func (r *MongoListRepository) DeleteMovie(ctx context.Context, imdbId string) error {
+	return deleteImdbId(ctx, r.collection, imdbId)
+}
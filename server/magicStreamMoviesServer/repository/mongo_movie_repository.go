@@ -0,0 +1,436 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// movieSortFields maps MovieQuery.SortField to the document field it orders by
+var movieSortFields = map[string]string{
+	SortByTitle:   "title",
+	SortByRanking: "ranking.ranking_value",
+}
+
+// MongoMovieRepository stores movies in a MongoDB collection
+type MongoMovieRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoMovieRepository creates a MovieRepository backed by collection
+func NewMongoMovieRepository(collection *mongo.Collection) *MongoMovieRepository {
+	return &MongoMovieRepository{collection: collection}
+}
+
+// mongoError maps driver errors onto the repository errors
+func mongoError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrNotFound
+	}
+
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+
+	return err
+}
+
+// moveImdbId re-points the documents of collection from one imdb_id to another
+func moveImdbId(ctx context.Context, collection *mongo.Collection, fromImdbId, toImdbId string) error {
+	_, err := collection.UpdateMany(ctx, bson.M{"imdb_id": fromImdbId}, bson.M{"$set": bson.M{"imdb_id": toImdbId}})
+
+	return err
+}
+
+// deleteImdbId removes the documents of collection with imdbId
+func deleteImdbId(ctx context.Context, collection *mongo.Collection, imdbId string) error {
+	_, err := collection.DeleteMany(ctx, bson.M{"imdb_id": imdbId})
+
+	return err
+}
+
+// distinctUsers returns the distinct user_id values of collection
+func distinctUsers(ctx context.Context, collection *mongo.Collection) ([]string, error) {
+	users := []string{}
+
+	err := collection.Distinct(ctx, "user_id", bson.M{}).Decode(&users)
+
+	return users, err
+}
+
+// deleteOne removes the first document matching filter, returning ErrNotFound when there is none
+func deleteOne(ctx context.Context, collection *mongo.Collection, filter bson.M) error {
+	result, err := collection.DeleteOne(ctx, filter)
+
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// movieFilter translates the filters of query into a MongoDB filter
+func movieFilter(query MovieQuery) bson.M {
+	filter := bson.M{}
+
+	if len(query.GenreNames) > 0 {
+		filter["genre.genre_name"] = bson.M{"$in": query.GenreNames}
+	}
+
+	if len(query.GenreIDs) > 0 {
+		filter["genre.genre_id"] = bson.M{"$in": query.GenreIDs}
+	}
+
+	if query.RankingName != "" {
+		filter["ranking.ranking_name"] = query.RankingName
+	}
+
+	rankingRange := bson.M{}
+
+	if query.MinRanking != nil {
+		rankingRange["$gte"] = *query.MinRanking
+	}
+
+	if query.MaxRanking != nil {
+		rankingRange["$lte"] = *query.MaxRanking
+	}
+
+	if len(rankingRange) > 0 {
+		filter["ranking.ranking_value"] = rankingRange
+	}
+
+	if query.TitlePrefix != "" {
+		filter["title"] = bson.M{"$regex": "^" + regexp.QuoteMeta(query.TitlePrefix), "$options": "i"}
+	}
+
+	imdbIds := bson.M{}
+
+	if query.ImdbIDs != nil {
+		imdbIds["$in"] = query.ImdbIDs
+	}
+
+	if len(query.ExcludeImdbIDs) > 0 {
+		imdbIds["$nin"] = query.ExcludeImdbIDs
+	}
+
+	if len(imdbIds) > 0 {
+		filter["imdb_id"] = imdbIds
+	}
+
+	if query.WithAdminReview {
+		filter["admin_review"] = bson.M{"$nin": bson.A{"", nil}}
+	}
+
+	if query.ExcludeRankingStatus != "" {
+		filter["ranking_status"] = bson.M{"$ne": query.ExcludeRankingStatus}
+	}
+
+	return filter
+}
+
+// cursorCondition selects the movies that come after cur when sorting by
+// field in the given direction (1 or -1).
+func cursorCondition(field string, direction int, cur MovieCursor) bson.M {
+	var lastValue any = cur.Title
+	if field == movieSortFields[SortByRanking] {
+		lastValue = cur.Ranking
+	}
+
+	op := "$gt"
+	if direction < 0 {
+		op = "$lt"
+	}
+
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{op: lastValue}},
+		bson.M{field: lastValue, "_id": bson.M{"$gt": cur.ID}},
+	}}
+}
+
+func (r *MongoMovieRepository) List(ctx context.Context, query MovieQuery) ([]models.Movie, error) {
+	field, ok := movieSortFields[query.SortField]
+
+	if !ok {
+		field = movieSortFields[SortByTitle]
+	}
+
+	direction := 1
+	if query.Descending {
+		direction = -1
+	}
+
+	filter := movieFilter(query)
+
+	if query.After != nil {
+		filter = bson.M{"$and": bson.A{filter, cursorCondition(field, direction, *query.After)}}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: field, Value: direction}, {Key: "_id", Value: 1}}).
+		SetSkip(query.Skip).
+		SetLimit(query.Limit)
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	movies := []models.Movie{}
+
+	if err := cursor.All(ctx, &movies); err != nil {
+		return nil, err
+	}
+
+	return movies, nil
+}
+
+func (r *MongoMovieRepository) Count(ctx context.Context, query MovieQuery) (int64, error) {
+	return r.collection.CountDocuments(ctx, movieFilter(query))
+}
+
+func (r *MongoMovieRepository) Get(ctx context.Context, imdbId string) (models.Movie, error) {
+	var movie models.Movie
+
+	err := r.collection.FindOne(ctx, bson.M{"imdb_id": imdbId}).Decode(&movie)
+
+	return movie, mongoError(err)
+}
+
+func (r *MongoMovieRepository) Insert(ctx context.Context, movie *models.Movie) error {
+	movie.ID = bson.NewObjectID()
+
+	_, err := r.collection.InsertOne(ctx, movie)
+
+	return mongoError(err)
+}
+
+func (r *MongoMovieRepository) Update(ctx context.Context, imdbId string, patch models.MoviePatch) (models.Movie, error) {
+	fields := bson.M{}
+
+	if patch.ImdbID != nil {
+		fields["imdb_id"] = *patch.ImdbID
+	}
+	if patch.Title != nil {
+		fields["title"] = *patch.Title
+	}
+	if patch.PosterPath != nil {
+		fields["poster_path"] = *patch.PosterPath
+	}
+	if patch.YouTubeID != nil {
+		fields["youtube_id"] = *patch.YouTubeID
+	}
+	if patch.Genre != nil {
+		fields["genre"] = *patch.Genre
+	}
+
+	var updated models.Movie
+
+	if len(fields) == 0 {
+		return r.Get(ctx, imdbId)
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"imdb_id": imdbId}, bson.M{"$set": fields}, opts).Decode(&updated)
+
+	return updated, mongoError(err)
+}
+
+func (r *MongoMovieRepository) Delete(ctx context.Context, imdbId string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"imdb_id": imdbId})
+
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// updateMovie applies update to the movie with imdbId, returning ErrNotFound when there is none
+func (r *MongoMovieRepository) updateMovie(ctx context.Context, imdbId string, update bson.M) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"imdb_id": imdbId}, update)
+
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoMovieRepository) SetAdminReview(ctx context.Context, imdbId, review, jobId string) error {
+	return r.updateMovie(ctx, imdbId, bson.M{"$set": bson.M{
+		"admin_review":   review,
+		"ranking_status": models.JobStatusPending,
+		"ranking_job_id": jobId,
+	}})
+}
+
+func (r *MongoMovieRepository) SetRankingResult(ctx context.Context, imdbId, jobId, status string, ranking *models.Ranking) error {
+	set := bson.M{"ranking_status": status}
+
+	if ranking != nil {
+		set["ranking"] = *ranking
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"imdb_id": imdbId, "ranking_job_id": jobId}, bson.M{"$set": set})
+
+	return err
+}
+
+func (r *MongoMovieRepository) SetRerankResult(ctx context.Context, imdbId, review string, ranking models.Ranking) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{
+			"imdb_id":        imdbId,
+			"admin_review":   review,
+			"ranking_status": bson.M{"$ne": models.JobStatusPending},
+		},
+		bson.M{"$set": bson.M{
+			"ranking":        ranking,
+			"ranking_status": models.JobStatusSucceeded,
+		}},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoMovieRepository) SetRating(ctx context.Context, imdbId string, average float64, count int) error {
+	return r.updateMovie(ctx, imdbId, bson.M{"$set": bson.M{"average_rating": average, "rating_count": count}})
+}
+
+func (r *MongoMovieRepository) ReplaceRanking(ctx context.Context, previous, current models.Ranking) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"ranking.ranking_value": previous.RankingValue, "ranking.ranking_name": previous.RankingName},
+		bson.M{"$set": bson.M{"ranking": current}},
+	)
+
+	return err
+}
+
+func (r *MongoMovieRepository) SetRankingValues(ctx context.Context, values map[string]int) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(values))
+
+	for rankingName, value := range values {
+		writes = append(writes, mongo.NewUpdateManyModel().
+			SetFilter(bson.M{"ranking.ranking_name": rankingName}).
+			SetUpdate(bson.M{"$set": bson.M{"ranking.ranking_value": value}}))
+	}
+
+	_, err := r.collection.BulkWrite(ctx, writes)
+
+	return err
+}
+
+func (r *MongoMovieRepository) CountWithRanking(ctx context.Context, rankingValue int) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"ranking.ranking_value": rankingValue})
+}
+
+func (r *MongoMovieRepository) CountWithGenre(ctx context.Context, genreId int) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"genre.genre_id": genreId})
+}
+
+func (r *MongoMovieRepository) RenameGenre(ctx context.Context, genre models.Genre) error {
+	return renameEmbeddedGenre(ctx, r.collection, "genre", genre)
+}
+
+func (r *MongoMovieRepository) TextSearch(ctx context.Context, query string, limit int64) ([]models.MovieSearchResult, error) {
+	score := bson.M{"$meta": "textScore"}
+
+	// relies on the movie_text_search index
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}}).
+		SetLimit(limit)
+
+	cursor, err := r.collection.Find(ctx, bson.M{"$text": bson.M{"$search": query}}, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []struct {
+		models.Movie `bson:",inline"`
+		Score        float64 `bson:"score"`
+	}
+
+	if err := cursor.All(ctx, &hits); err != nil {
+		return nil, err
+	}
+
+	results := make([]models.MovieSearchResult, 0, len(hits))
+
+	for _, hit := range hits {
+		results = append(results, models.MovieSearchResult{Movie: hit.Movie, Score: hit.Score})
+	}
+
+	return results, nil
+}
+
+func (r *MongoMovieRepository) ListByTitleWordPrefix(ctx context.Context, prefixes []string, limit int64) ([]models.Movie, error) {
+	movies := []models.Movie{}
+
+	if len(prefixes) == 0 {
+		return movies, nil
+	}
+
+	conditions := make(bson.A, 0, len(prefixes))
+
+	for _, prefix := range prefixes {
+		conditions = append(conditions, bson.M{"title": bson.M{
+			"$regex":   `\b` + regexp.QuoteMeta(prefix),
+			"$options": "i",
+		}})
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"$or": conditions}, options.Find().SetLimit(limit))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cursor.All(ctx, &movies); err != nil {
+		return nil, err
+	}
+
+	return movies, nil
+}
+
+// renameEmbeddedGenre rewrites the name of every copy of genre embedded in the array field
+func renameEmbeddedGenre(ctx context.Context, collection *mongo.Collection, field string, genre models.Genre) error {
+	_, err := collection.UpdateMany(ctx,
+		bson.M{field + ".genre_id": genre.GenreID},
+		bson.M{"$set": bson.M{field + ".$[g].genre_name": genre.GenreName}},
+		options.UpdateMany().SetArrayFilters([]any{bson.M{"g.genre_id": genre.GenreID}}),
+	)
+
+	return err
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoRankingJobRepository stores ranking jobs in a MongoDB collection
+type MongoRankingJobRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRankingJobRepository creates a RankingJobRepository backed by collection
+func NewMongoRankingJobRepository(collection *mongo.Collection) *MongoRankingJobRepository {
+	return &MongoRankingJobRepository{collection: collection}
+}
+
+func (r *MongoRankingJobRepository) Insert(ctx context.Context, job models.RankingJob) error {
+	_, err := r.collection.InsertOne(ctx, job)
+
+	return mongoError(err)
+}
+
+func (r *MongoRankingJobRepository) Get(ctx context.Context, id bson.ObjectID) (models.RankingJob, error) {
+	var job models.RankingJob
+
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&job)
+
+	return job, mongoError(err)
+}
+
+func (r *MongoRankingJobRepository) Claim(ctx context.Context, id bson.ObjectID, now, leaseUntil time.Time) (models.RankingJob, error) {
+	var job models.RankingJob
+
+	// The filter makes the claim atomic, so a job queued twice or seen by two replicas is only run once
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": models.JobStatusPending, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{
+			"$set": bson.M{"status": models.JobStatusRunning, "lease_expires_at": leaseUntil, "updated_at": now},
+			"$inc": bson.M{"attempts": 1},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&job)
+
+	return job, mongoError(err)
+}
+
+func (r *MongoRankingJobRepository) Finish(ctx context.Context, id bson.ObjectID, attempt int, outcome JobOutcome) error {
+	set := bson.M{"status": outcome.Status, "updated_at": time.Now()}
+
+	if outcome.Ranking != nil {
+		set["ranking"] = *outcome.Ranking
+	}
+	if outcome.LastError != nil {
+		set["last_error"] = *outcome.LastError
+	}
+	if outcome.NextAttemptAt != nil {
+		set["next_attempt_at"] = *outcome.NextAttemptAt
+	}
+	if outcome.Attempts != nil {
+		set["attempts"] = *outcome.Attempts
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": models.JobStatusRunning, "attempts": attempt},
+		bson.M{"$set": set, "$unset": bson.M{"lease_expires_at": ""}},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoRankingJobRepository) Due(ctx context.Context, now time.Time, limit int64) ([]bson.ObjectID, error) {
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 1}).
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := r.collection.Find(ctx, bson.M{"status": models.JobStatusPending, "next_attempt_at": bson.M{"$lte": now}}, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var due []struct {
+		ID bson.ObjectID `bson:"_id"`
+	}
+
+	if err := cursor.All(ctx, &due); err != nil {
+		return nil, err
+	}
+
+	ids := make([]bson.ObjectID, 0, len(due))
+
+	for _, job := range due {
+		ids = append(ids, job.ID)
+	}
+
+	return ids, nil
+}
+
+func (r *MongoRankingJobRepository) ReleaseExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"status": models.JobStatusRunning, "lease_expires_at": bson.M{"$lt": now}},
+		bson.M{
+			"$set":   bson.M{"status": models.JobStatusPending, "next_attempt_at": now, "updated_at": now},
+			"$unset": bson.M{"lease_expires_at": ""},
+		},
+	)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
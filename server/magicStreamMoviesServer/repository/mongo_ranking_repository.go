@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoRankingRepository stores the ranking scale in a MongoDB collection
+type MongoRankingRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRankingRepository creates a RankingRepository backed by collection
+func NewMongoRankingRepository(collection *mongo.Collection) *MongoRankingRepository {
+	return &MongoRankingRepository{collection: collection}
+}
+
+func (r *MongoRankingRepository) List(ctx context.Context) ([]models.Ranking, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "ranking_value", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rankings := []models.Ranking{}
+
+	if err := cursor.All(ctx, &rankings); err != nil {
+		return nil, err
+	}
+
+	return rankings, nil
+}
+
+func (r *MongoRankingRepository) Exists(ctx context.Context, ranking models.Ranking) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"ranking_value": ranking.RankingValue,
+		"ranking_name":  ranking.RankingName,
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *MongoRankingRepository) Insert(ctx context.Context, ranking models.Ranking) error {
+	_, err := r.collection.InsertOne(ctx, ranking)
+
+	return mongoError(err)
+}
+
+func (r *MongoRankingRepository) Replace(ctx context.Context, value int, ranking models.Ranking) (models.Ranking, error) {
+	var previous models.Ranking
+
+	err := r.collection.FindOneAndReplace(ctx, bson.M{"ranking_value": value}, ranking).Decode(&previous)
+
+	return previous, mongoError(err)
+}
+
+func (r *MongoRankingRepository) Reorder(ctx context.Context, order []string) error {
+	writes := make([]mongo.WriteModel, 0, 2*len(order))
+
+	// Move every ranking to a temporary negative value first so the unique
+	// ranking_value index never sees two rankings with the same value. The
+	// bulk write is ordered, so the final values are only set once every
+	// ranking has left its old one.
+	for i, name := range order {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"ranking_name": name}).
+			SetUpdate(bson.M{"$set": bson.M{"ranking_value": -(i + 1)}}))
+	}
+
+	for i, name := range order {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"ranking_name": name}).
+			SetUpdate(bson.M{"$set": bson.M{"ranking_value": i + 1}}))
+	}
+
+	_, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(true))
+
+	return mongoError(err)
+}
+
+func (r *MongoRankingRepository) Delete(ctx context.Context, value int) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"ranking_value": value})
+
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoRankingRepository) ExcludeFromAI(ctx context.Context, value int) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"ranking_value": value, "excluded_from_ai": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"excluded_from_ai": true}},
+	)
+
+	return err
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoRerankRunRepository stores re-rank runs in a MongoDB collection and
+// their diffs and failures in one collection each
+type MongoRerankRunRepository struct {
+	runs     *mongo.Collection
+	diffs    *mongo.Collection
+	failures *mongo.Collection
+}
+
+// NewMongoRerankRunRepository creates a RerankRunRepository backed by the given collections
+func NewMongoRerankRunRepository(runs, diffs, failures *mongo.Collection) *MongoRerankRunRepository {
+	return &MongoRerankRunRepository{runs: runs, diffs: diffs, failures: failures}
+}
+
+func (r *MongoRerankRunRepository) Insert(ctx context.Context, run models.RerankRun) error {
+	_, err := r.runs.InsertOne(ctx, run)
+
+	return mongoError(err)
+}
+
+func (r *MongoRerankRunRepository) Get(ctx context.Context, id bson.ObjectID) (models.RerankRun, error) {
+	var run models.RerankRun
+
+	err := r.runs.FindOne(ctx, bson.M{"_id": id}).Decode(&run)
+
+	return run, mongoError(err)
+}
+
+func (r *MongoRerankRunRepository) Save(ctx context.Context, run models.RerankRun) error {
+	_, err := r.runs.ReplaceOne(ctx, bson.M{"_id": run.ID}, run)
+
+	return err
+}
+
+func (r *MongoRerankRunRepository) AddDiff(ctx context.Context, diff models.RankingDiff) error {
+	_, err := r.diffs.InsertOne(ctx, diff)
+
+	return err
+}
+
+func (r *MongoRerankRunRepository) AddFailure(ctx context.Context, failure models.RerankFailure) error {
+	_, err := r.failures.InsertOne(ctx, failure)
+
+	return err
+}
+
+// findRunEntries decodes a page of the entries of the run stored in collection into results
+func findRunEntries(ctx context.Context, collection *mongo.Collection, runId bson.ObjectID, skip, limit int64, results any) error {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetSkip(skip).
+		SetLimit(limit)
+
+	cursor, err := collection.Find(ctx, bson.M{"run_id": runId}, opts)
+
+	if err != nil {
+		return err
+	}
+
+	return cursor.All(ctx, results)
+}
+
+func (r *MongoRerankRunRepository) Diffs(ctx context.Context, runId bson.ObjectID, skip, limit int64) ([]models.RankingDiff, error) {
+	diffs := []models.RankingDiff{}
+
+	if err := findRunEntries(ctx, r.diffs, runId, skip, limit, &diffs); err != nil {
+		return nil, err
+	}
+
+	return diffs, nil
+}
+
+func (r *MongoRerankRunRepository) Failures(ctx context.Context, runId bson.ObjectID, skip, limit int64) ([]models.RerankFailure, error) {
+	failures := []models.RerankFailure{}
+
+	if err := findRunEntries(ctx, r.failures, runId, skip, limit, &failures); err != nil {
+		return nil, err
+	}
+
+	return failures, nil
+}
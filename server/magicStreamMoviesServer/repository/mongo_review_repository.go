@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoReviewRepository stores user reviews in a MongoDB collection
+type MongoReviewRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoReviewRepository creates a ReviewRepository backed by collection
+func NewMongoReviewRepository(collection *mongo.Collection) *MongoReviewRepository {
+	return &MongoReviewRepository{collection: collection}
+}
+
+// findReviews returns the reviews matching filter in the order of opts
+func (r *MongoReviewRepository) findReviews(ctx context.Context, filter bson.M, opts ...options.Lister[options.FindOptions]) ([]models.UserReview, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reviews := []models.UserReview{}
+
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+func (r *MongoReviewRepository) Insert(ctx context.Context, review *models.UserReview) error {
+	review.ID = bson.NewObjectID()
+
+	_, err := r.collection.InsertOne(ctx, review)
+
+	return mongoError(err)
+}
+
+func (r *MongoReviewRepository) Update(ctx context.Context, imdbId, userId string, input models.UserReviewInput) (models.UserReview, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var review models.UserReview
+
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"imdb_id": imdbId, "user_id": userId},
+		bson.M{"$set": bson.M{"rating": input.Rating, "text": input.Text, "updated_at": time.Now()}},
+		opts,
+	).Decode(&review)
+
+	return review, mongoError(err)
+}
+
+func (r *MongoReviewRepository) Delete(ctx context.Context, imdbId, userId string) error {
+	return deleteOne(ctx, r.collection, bson.M{"imdb_id": imdbId, "user_id": userId})
+}
+
+func (r *MongoReviewRepository) ListByMovie(ctx context.Context, imdbId string, skip, limit int64) ([]models.UserReview, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(limit)
+
+	return r.findReviews(ctx, bson.M{"imdb_id": imdbId}, opts)
+}
+
+func (r *MongoReviewRepository) CountByMovie(ctx context.Context, imdbId string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"imdb_id": imdbId})
+}
+
+func (r *MongoReviewRepository) ListByUser(ctx context.Context, userId string) ([]models.UserReview, error) {
+	return r.findReviews(ctx, bson.M{"user_id": userId})
+}
+
+func (r *MongoReviewRepository) Rating(ctx context.Context, imdbId string) (Rating, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"imdb_id": imdbId}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"average": bson.M{"$avg": "$rating"},
+			"count":   bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+
+	if err != nil {
+		return Rating{}, err
+	}
+
+	var stats []struct {
+		Average float64 `bson:"average"`
+		Count   int     `bson:"count"`
+	}
+
+	if err := cursor.All(ctx, &stats); err != nil {
+		return Rating{}, err
+	}
+
+	if len(stats) == 0 {
+		return Rating{}, nil
+	}
+
+	return Rating{Average: math.Round(stats[0].Average*100) / 100, Count: stats[0].Count}, nil
+}
+
+func (r *MongoReviewRepository) Users(ctx context.Context) ([]string, error) {
+	return distinctUsers(ctx, r.collection)
+}
+
+func (r *MongoReviewRepository) MoveMovie(ctx context.Context, fromImdbId, toImdbId string) error {
+	return moveImdbId(ctx, r.collection, fromImdbId, toImdbId)
+}
+
+func (r *MongoReviewRepository) DeleteMovie(ctx context.Context, imdbId string) error {
+	return deleteImdbId(ctx, r.collection, imdbId)
+}
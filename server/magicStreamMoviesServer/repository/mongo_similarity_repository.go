@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoSimilarityRepository stores the item-item movie similarities in a MongoDB collection
+type MongoSimilarityRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoSimilarityRepository creates a SimilarityRepository backed by collection
+func NewMongoSimilarityRepository(collection *mongo.Collection) *MongoSimilarityRepository {
+	return &MongoSimilarityRepository{collection: collection}
+}
+
+func (r *MongoSimilarityRepository) Count(ctx context.Context) (int64, error) {
+	return r.collection.EstimatedDocumentCount(ctx)
+}
+
+func (r *MongoSimilarityRepository) ListByMovies(ctx context.Context, imdbIds []string) ([]models.MovieSimilarity, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"imdb_id": bson.M{"$in": imdbIds}})
+
+	if err != nil {
+		return nil, err
+	}
+
+	similarities := []models.MovieSimilarity{}
+
+	if err := cursor.All(ctx, &similarities); err != nil {
+		return nil, err
+	}
+
+	return similarities, nil
+}
+
+func (r *MongoSimilarityRepository) Save(ctx context.Context, imdbId string, neighbours []models.Neighbour) error {
+	if len(neighbours) == 0 {
+		_, err := r.collection.DeleteOne(ctx, bson.M{"imdb_id": imdbId})
+		return err
+	}
+
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"imdb_id": imdbId},
+		bson.M{"$set": bson.M{"neighbours": neighbours, "updated_at": time.Now()}},
+		options.UpdateOne().SetUpsert(true),
+	)
+
+	return err
+}
+
+func (r *MongoSimilarityRepository) ReplaceNeighbour(ctx context.Context, imdbId string, scores map[string]float64, keep int) error {
+	if _, err := r.collection.UpdateMany(ctx,
+		bson.M{"neighbours.imdb_id": imdbId},
+		bson.M{"$pull": bson.M{"neighbours": bson.M{"imdb_id": imdbId}}},
+	); err != nil {
+		return err
+	}
+
+	if len(scores) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(scores))
+
+	for other, score := range scores {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"imdb_id": other}).
+			SetUpdate(bson.M{
+				"$push": bson.M{"neighbours": bson.M{
+					"$each":  []models.Neighbour{{ImdbID: imdbId, Score: score}},
+					"$sort":  bson.M{"score": -1},
+					"$slice": keep,
+				}},
+				"$set": bson.M{"updated_at": time.Now()},
+			}).
+			SetUpsert(true))
+	}
+
+	_, err := r.collection.BulkWrite(ctx, writes)
+
+	return err
+}
+
+func (r *MongoSimilarityRepository) MoveMovie(ctx context.Context, fromImdbId, toImdbId string) error {
+	if _, err := r.collection.UpdateOne(ctx,
+		bson.M{"imdb_id": fromImdbId},
+		bson.M{"$set": bson.M{"imdb_id": toImdbId}},
+	); err != nil {
+		return err
+	}
+
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"neighbours.imdb_id": fromImdbId},
+		bson.M{"$set": bson.M{"neighbours.$[n].imdb_id": toImdbId}},
+		options.UpdateMany().SetArrayFilters([]any{bson.M{"n.imdb_id": fromImdbId}}),
+	)
+
+	return err
+}
+
+// DeleteMovie removes the movie's similarities and its entries in other
+// movies' neighbour lists. The similarity between two other movies does not
+// depend on it, so nothing else needs recomputing.
+func (r *MongoSimilarityRepository) DeleteMovie(ctx context.Context, imdbId string) error {
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"imdb_id": imdbId}); err != nil {
+		return err
+	}
+
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"neighbours.imdb_id": imdbId},
+		bson.M{"$pull": bson.M{"neighbours": bson.M{"imdb_id": imdbId}}},
+	)
+
+	return err
+}
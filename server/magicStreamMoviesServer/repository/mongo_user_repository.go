@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoUserRepository stores users in a MongoDB collection
+type MongoUserRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoUserRepository creates a UserRepository backed by collection
+func NewMongoUserRepository(collection *mongo.Collection) *MongoUserRepository {
+	return &MongoUserRepository{collection: collection}
+}
+
+// userFilter translates the filters of query into a MongoDB filter
+func userFilter(query UserQuery) bson.M {
+	filter := bson.M{}
+
+	if query.Role != "" {
+		filter["role"] = query.Role
+	}
+
+	return filter
+}
+
+// userUpdateFields returns the $set document for update
+func userUpdateFields(update UserUpdate) bson.M {
+	fields := bson.M{"updated_at": time.Now()}
+
+	if update.Role != nil {
+		fields["role"] = *update.Role
+	}
+
+	if update.Disabled != nil {
+		fields["disabled"] = *update.Disabled
+	}
+
+	return fields
+}
+
+func (r *MongoUserRepository) Insert(ctx context.Context, user *models.User) error {
+	user.ID = bson.NewObjectID()
+
+	_, err := r.collection.InsertOne(ctx, user)
+
+	return mongoError(err)
+}
+
+func (r *MongoUserRepository) Get(ctx context.Context, userId string) (models.User, error) {
+	var user models.User
+
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userId}).Decode(&user)
+
+	return user, mongoError(err)
+}
+
+func (r *MongoUserRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	var user models.User
+
+	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+
+	return user, mongoError(err)
+}
+
+func (r *MongoUserRepository) List(ctx context.Context, query UserQuery) ([]models.User, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetSkip(query.Skip).
+		SetLimit(query.Limit)
+
+	cursor, err := r.collection.Find(ctx, userFilter(query), opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	users := []models.User{}
+
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *MongoUserRepository) Count(ctx context.Context, query UserQuery) (int64, error) {
+	return r.collection.CountDocuments(ctx, userFilter(query))
+}
+
+// updateOne applies update to the first user matching filter and returns it
+func (r *MongoUserRepository) updateOne(ctx context.Context, filter bson.M, update UserUpdate) (models.User, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var user models.User
+
+	err := r.collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": userUpdateFields(update)}, opts).Decode(&user)
+
+	return user, mongoError(err)
+}
+
+func (r *MongoUserRepository) Update(ctx context.Context, userId string, update UserUpdate) (models.User, error) {
+	return r.updateOne(ctx, bson.M{"user_id": userId}, update)
+}
+
+func (r *MongoUserRepository) UpdateByEmail(ctx context.Context, email string, update UserUpdate) (models.User, error) {
+	return r.updateOne(ctx, bson.M{"email": email}, update)
+}
+
+func (r *MongoUserRepository) Delete(ctx context.Context, userId string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userId})
+
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoUserRepository) CountWithGenre(ctx context.Context, genreId int) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"favourite_genres.genre_id": genreId})
+}
+
+func (r *MongoUserRepository) RenameGenre(ctx context.Context, genre models.Genre) error {
+	return renameEmbeddedGenre(ctx, r.collection, "favourite_genres", genre)
+}
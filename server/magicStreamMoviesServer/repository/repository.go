@@ -0,0 +1,385 @@
+// Package repository hides how movies, users and their data are stored behind
+// interfaces, with a MongoDB implementation for the server and an in-memory
+// implementation for tests and local tooling.
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// ErrNotFound is returned when the requested document does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrDuplicate is returned when a write would break a uniqueness rule
+var ErrDuplicate = errors.New("duplicate key")
+
+// Movie sort fields understood by MovieQuery.SortField
+const (
+	SortByTitle   = "title"
+	SortByRanking = "ranking"
+)
+
+// MovieQuery selects and orders movies. Zero values mean "no filter".
+type MovieQuery struct {
+	GenreNames  []string
+	GenreIDs    []int
+	RankingName string
+	MinRanking  *int
+	MaxRanking  *int
+	// TitlePrefix matches the start of the title, ignoring case
+	TitlePrefix string
+	// ImdbIDs keeps only the listed movies, ExcludeImdbIDs drops the listed movies
+	ImdbIDs        []string
+	ExcludeImdbIDs []string
+	// WithAdminReview keeps only movies with a non-empty admin review
+	WithAdminReview bool
+	// ExcludeRankingStatus drops movies whose ranking_status is this status
+	ExcludeRankingStatus string
+
+	SortField  string
+	Descending bool
+	// After selects the movies that come after a previous page's last movie (keyset pagination)
+	After *MovieCursor
+	Skip  int64
+	Limit int64
+}
+
+// MovieCursor is the sort key and _id of the last movie of a page
+type MovieCursor struct {
+	Title   string
+	Ranking int
+	ID      bson.ObjectID
+}
+
+type MovieRepository interface {
+	List(ctx context.Context, query MovieQuery) ([]models.Movie, error)
+	// Count ignores the pagination fields of query
+	Count(ctx context.Context, query MovieQuery) (int64, error)
+	Get(ctx context.Context, imdbId string) (models.Movie, error)
+	// Insert stores movie and sets its ID
+	Insert(ctx context.Context, movie *models.Movie) error
+	// Update sets the non-nil fields of patch and returns the updated movie
+	Update(ctx context.Context, imdbId string, patch models.MoviePatch) (models.Movie, error)
+	Delete(ctx context.Context, imdbId string) error
+	// SetAdminReview saves a review whose ranking is pending in the given job
+	SetAdminReview(ctx context.Context, imdbId, review, jobId string) error
+	// SetRankingResult records the outcome of the given ranking job, and the
+	// ranking when not nil, unless a newer job has replaced it on the movie
+	SetRankingResult(ctx context.Context, imdbId, jobId, status string, ranking *models.Ranking) error
+	SetRating(ctx context.Context, imdbId string, average float64, count int) error
+	// ReplaceRanking gives every movie ranked previous the ranking current
+	ReplaceRanking(ctx context.Context, previous, current models.Ranking) error
+	// SetRerankResult stores the ranking found by a re-rank on the movie.
+	// ErrNotFound is returned when the movie is gone, its admin review is no
+	// longer review or a queued ranking of it is pending.
+	SetRerankResult(ctx context.Context, imdbId, review string, ranking models.Ranking) error
+	// SetRankingValues renumbers, on every movie, each ranking named in values
+	// to its new value in a single write
+	SetRankingValues(ctx context.Context, values map[string]int) error
+	CountWithRanking(ctx context.Context, rankingValue int) (int64, error)
+	CountWithGenre(ctx context.Context, genreId int) (int64, error)
+	// RenameGenre updates the embedded copies of genre
+	RenameGenre(ctx context.Context, genre models.Genre) error
+	// TextSearch returns the movies whose title or admin review contain the
+	// words of query, most relevant first; title matches weigh more
+	TextSearch(ctx context.Context, query string, limit int64) ([]models.MovieSearchResult, error)
+	// ListByTitleWordPrefix returns up to limit movies with a title word
+	// starting with any of prefixes, ignoring case
+	ListByTitleWordPrefix(ctx context.Context, prefixes []string, limit int64) ([]models.Movie, error)
+}
+
+// UserQuery selects a page of users; an empty Role matches every role
+type UserQuery struct {
+	Role  string
+	Skip  int64
+	Limit int64
+}
+
+// UserUpdate holds the account fields an admin can change; nil fields are left unchanged
+type UserUpdate struct {
+	Role     *string
+	Disabled *bool
+}
+
+type UserRepository interface {
+	// Insert stores user; ErrDuplicate is returned when the email is taken
+	Insert(ctx context.Context, user *models.User) error
+	Get(ctx context.Context, userId string) (models.User, error)
+	GetByEmail(ctx context.Context, email string) (models.User, error)
+	// List returns users ordered by creation time
+	List(ctx context.Context, query UserQuery) ([]models.User, error)
+	// Count ignores the pagination fields of query
+	Count(ctx context.Context, query UserQuery) (int64, error)
+	Update(ctx context.Context, userId string, update UserUpdate) (models.User, error)
+	// UpdateByEmail is like Update but finds the user by email
+	UpdateByEmail(ctx context.Context, email string, update UserUpdate) (models.User, error)
+	Delete(ctx context.Context, userId string) error
+	CountWithGenre(ctx context.Context, genreId int) (int64, error)
+	// RenameGenre updates the embedded copies of genre in favourite genres
+	RenameGenre(ctx context.Context, genre models.Genre) error
+}
+
+type RankingRepository interface {
+	// List returns the rankings ordered by ranking_value, best first
+	List(ctx context.Context) ([]models.Ranking, error)
+	// Exists reports whether a ranking with both the value and the name exists
+	Exists(ctx context.Context, ranking models.Ranking) (bool, error)
+	Insert(ctx context.Context, ranking models.Ranking) error
+	// Replace swaps the ranking with the given value for ranking and returns the previous one
+	Replace(ctx context.Context, value int, ranking models.Ranking) (models.Ranking, error)
+	// Reorder gives the rankings named in order the values 1..n in that order,
+	// in a single write that never stores two rankings with the same value
+	Reorder(ctx context.Context, order []string) error
+	Delete(ctx context.Context, value int) error
+	// ExcludeFromAI flags the ranking with the given value when it has no
+	// stored excluded_from_ai, so an explicit false set by an admin is kept
+	ExcludeFromAI(ctx context.Context, value int) error
+}
+
+// GenreNameKey is the comparison key of a genre name; names with the same key
+// ("Sci-fi", "Sci-Fi", "sci fi") are the same genre and cannot both be stored.
+func GenreNameKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+type GenreRepository interface {
+	// List returns the genres ordered by name
+	List(ctx context.Context) ([]models.Genre, error)
+	// GetMany returns the stored genres among ids, in no particular order
+	GetMany(ctx context.Context, ids []int) ([]models.Genre, error)
+	// Insert stores genre; ErrDuplicate is returned when its id or name key is taken
+	Insert(ctx context.Context, genre models.Genre) error
+	// NextID returns one more than the highest genre_id in use
+	NextID(ctx context.Context) (int, error)
+	// Update renames the genre with genre's id
+	Update(ctx context.Context, genre models.Genre) error
+	Delete(ctx context.Context, genreId int) error
+}
+
+// MovieReferenceRepository is implemented by the repositories that keep
+// per-user data pointing at a movie by imdb_id
+type MovieReferenceRepository interface {
+	// MoveMovie re-points the data of a movie whose imdb_id changed
+	MoveMovie(ctx context.Context, fromImdbId, toImdbId string) error
+	// DeleteMovie removes the data of a deleted movie
+	DeleteMovie(ctx context.Context, imdbId string) error
+}
+
+// Rating is the average star rating of a movie and how many reviews it is based on
+type Rating struct {
+	Average float64
+	Count   int
+}
+
+type ReviewRepository interface {
+	MovieReferenceRepository
+	// Insert stores review and sets its ID; ErrDuplicate is returned when the
+	// user already reviewed the movie
+	Insert(ctx context.Context, review *models.UserReview) error
+	// Update changes the rating and text of the user's review of the movie and returns it
+	Update(ctx context.Context, imdbId, userId string, input models.UserReviewInput) (models.UserReview, error)
+	Delete(ctx context.Context, imdbId, userId string) error
+	// ListByMovie returns a page of the movie's reviews, newest first
+	ListByMovie(ctx context.Context, imdbId string, skip, limit int64) ([]models.UserReview, error)
+	CountByMovie(ctx context.Context, imdbId string) (int64, error)
+	ListByUser(ctx context.Context, userId string) ([]models.UserReview, error)
+	// Rating returns the movie's average rating, zero when it has no reviews
+	Rating(ctx context.Context, imdbId string) (Rating, error)
+	// Users returns the users that reviewed any movie
+	Users(ctx context.Context) ([]string, error)
+}
+
+type ListRepository interface {
+	MovieReferenceRepository
+	// Entries returns the user's list with the movies joined in, skipping
+	// movies deleted since they were saved. The watchlist is in the user's
+	// order, favourites newest first.
+	Entries(ctx context.Context, userId, list string) ([]models.ListEntry, error)
+	// Items returns the items of the user's list; an empty list returns the items of every list
+	Items(ctx context.Context, userId, list string) ([]models.ListItem, error)
+	// Add appends item to the end of its list and sets its ID and Position;
+	// ErrDuplicate is returned when the movie is already in the list
+	Add(ctx context.Context, item *models.ListItem) error
+	Remove(ctx context.Context, userId, list, imdbId string) error
+	// Reorder gives the movies in order the positions 1..n in that order
+	Reorder(ctx context.Context, userId, list string, order []string) error
+	// Users returns the users that saved any movie
+	Users(ctx context.Context) ([]string, error)
+}
+
+// HistoryQuery selects a user's watch history. Only entries with at least
+// MinProgress are returned; Unfinished drops completed titles.
+type HistoryQuery struct {
+	UserID      string
+	Unfinished  bool
+	MinProgress float64
+	Skip        int64
+	Limit       int64
+}
+
+type HistoryRepository interface {
+	MovieReferenceRepository
+	// Record saves watched as the user's latest playback state of the movie
+	// and returns it; StartedAt is kept from the first record
+	Record(ctx context.Context, watched models.WatchProgress) (models.WatchProgress, error)
+	// List returns the entries matching query with the movies joined in, most recent first
+	List(ctx context.Context, query HistoryQuery) ([]models.HistoryEntry, error)
+	// Count ignores the pagination fields of query
+	Count(ctx context.Context, query HistoryQuery) (int64, error)
+	ListByUser(ctx context.Context, userId string) ([]models.WatchProgress, error)
+	Delete(ctx context.Context, userId, imdbId string) error
+	// Users returns the users with any watch history
+	Users(ctx context.Context) ([]string, error)
+}
+
+type InteractionRepository interface {
+	MovieReferenceRepository
+	ListByUser(ctx context.Context, userId string) ([]models.Interaction, error)
+	ListByMovie(ctx context.Context, imdbId string) ([]models.Interaction, error)
+	ListByUsers(ctx context.Context, userIds []string) ([]models.Interaction, error)
+	// Replace stores weights, by imdb_id, as the user's interactions
+	Replace(ctx context.Context, userId string, weights map[string]float64) error
+	// Norms returns the euclidean norm of each movie's interaction weights
+	Norms(ctx context.Context, imdbIds []string) (map[string]float64, error)
+	// Users returns the users with stored interactions
+	Users(ctx context.Context) ([]string, error)
+}
+
+type SimilarityRepository interface {
+	MovieReferenceRepository
+	Count(ctx context.Context) (int64, error)
+	ListByMovies(ctx context.Context, imdbIds []string) ([]models.MovieSimilarity, error)
+	// Save stores neighbours as the movie's neighbours, or removes its entry when there are none
+	Save(ctx context.Context, imdbId string, neighbours []models.Neighbour) error
+	// ReplaceNeighbour removes imdbId from every neighbour list, then adds it
+	// to the lists of the movies in scores with their score, keeping the best keep
+	ReplaceNeighbour(ctx context.Context, imdbId string, scores map[string]float64, keep int) error
+}
+
+// JobOutcome is the result of one attempt at a ranking job; nil fields are left unchanged
+type JobOutcome struct {
+	Status        string
+	Ranking       *models.Ranking
+	LastError     *string
+	NextAttemptAt *time.Time
+	// Attempts replaces the attempt count, e.g. to give back an interrupted attempt
+	Attempts *int
+}
+
+type RankingJobRepository interface {
+	Insert(ctx context.Context, job models.RankingJob) error
+	Get(ctx context.Context, id bson.ObjectID) (models.RankingJob, error)
+	// Claim marks a pending job that is due at now as running until leaseUntil
+	// and returns it with its attempts incremented. ErrNotFound is returned
+	// when the job does not exist or is not claimable.
+	Claim(ctx context.Context, id bson.ObjectID, now, leaseUntil time.Time) (models.RankingJob, error)
+	// Finish records outcome on a running job, provided it is still held by
+	// the claim that made the given attempt; otherwise ErrNotFound is returned.
+	Finish(ctx context.Context, id bson.ObjectID, attempt int, outcome JobOutcome) error
+	// Due returns the ids of pending jobs due at now, oldest first
+	Due(ctx context.Context, now time.Time, limit int64) ([]bson.ObjectID, error)
+	// ReleaseExpired makes running jobs whose lease expired before now pending again
+	ReleaseExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type RerankRunRepository interface {
+	Insert(ctx context.Context, run models.RerankRun) error
+	Get(ctx context.Context, id bson.ObjectID) (models.RerankRun, error)
+	// Save replaces the stored run with run
+	Save(ctx context.Context, run models.RerankRun) error
+	AddDiff(ctx context.Context, diff models.RankingDiff) error
+	AddFailure(ctx context.Context, failure models.RerankFailure) error
+	// Diffs returns a page of the run's diffs in the order they were added
+	Diffs(ctx context.Context, runId bson.ObjectID, skip, limit int64) ([]models.RankingDiff, error)
+	// Failures returns a page of the run's failures in the order they were added
+	Failures(ctx context.Context, runId bson.ObjectID, skip, limit int64) ([]models.RerankFailure, error)
+}
+
+type EmbeddingRepository interface {
+	// Get returns the embedding of the movie made with model
+	Get(ctx context.Context, imdbId, model string) (models.MovieEmbedding, error)
+	// ListByModel returns every embedding made with model
+	ListByModel(ctx context.Context, model string) ([]models.MovieEmbedding, error)
+	// Hashes returns the text hash of every embedding made with model, by imdb_id
+	Hashes(ctx context.Context, model string) (map[string]string, error)
+	// Save stores embeddings, replacing those of the same movie and model
+	Save(ctx context.Context, embeddings []models.MovieEmbedding) error
+	// DeleteMovies removes the embeddings of the given movies
+	DeleteMovies(ctx context.Context, imdbIds []string) error
+	// DeleteOtherModels removes the embeddings not made with model
+	DeleteOtherModels(ctx context.Context, model string) error
+}
+
+// Repositories bundles the repositories the controllers depend on
+type Repositories struct {
+	Movies       MovieRepository
+	Users        UserRepository
+	Rankings     RankingRepository
+	Jobs         RankingJobRepository
+	Genres       GenreRepository
+	Reviews      ReviewRepository
+	Lists        ListRepository
+	History      HistoryRepository
+	Interactions InteractionRepository
+	Similarities SimilarityRepository
+	RerankRuns   RerankRunRepository
+	Embeddings   EmbeddingRepository
+}
+
+// MovieReferences returns the repositories that must follow a movie when its
+// imdb_id changes or it is deleted
+func (r *Repositories) MovieReferences() []MovieReferenceRepository {
+	return []MovieReferenceRepository{r.Reviews, r.Lists, r.History, r.Interactions, r.Similarities}
+}
+
+// NewMongoRepositories creates repositories backed by the collections of db
+func NewMongoRepositories(db *mongo.Database) *Repositories {
+	return &Repositories{
+		Movies:       NewMongoMovieRepository(db.Collection("movies")),
+		Users:        NewMongoUserRepository(db.Collection("users")),
+		Rankings:     NewMongoRankingRepository(db.Collection("rankings")),
+		Jobs:         NewMongoRankingJobRepository(db.Collection("ranking_jobs")),
+		Genres:       NewMongoGenreRepository(db.Collection("genres")),
+		Reviews:      NewMongoReviewRepository(db.Collection("reviews")),
+		Lists:        NewMongoListRepository(db.Collection("user_lists")),
+		History:      NewMongoHistoryRepository(db.Collection("watch_history")),
+		Interactions: NewMongoInteractionRepository(db.Collection("interactions")),
+		Similarities: NewMongoSimilarityRepository(db.Collection("movie_similarities")),
+		RerankRuns:   NewMongoRerankRunRepository(db.Collection("rerank_runs"), db.Collection("rerank_diffs"), db.Collection("rerank_failures")),
+		Embeddings:   NewMongoEmbeddingRepository(db.Collection("movie_embeddings")),
+	}
+}
+
+// NewMemoryRepositories creates empty in-memory repositories. Lists and
+// history join in the movies of the returned movie repository.
+func NewMemoryRepositories() *Repositories {
+	movies := NewMemoryMovieRepository()
+
+	return &Repositories{
+		Movies:       movies,
+		Users:        NewMemoryUserRepository(),
+		Rankings:     NewMemoryRankingRepository(),
+		Jobs:         NewMemoryRankingJobRepository(),
+		Genres:       NewMemoryGenreRepository(),
+		Reviews:      NewMemoryReviewRepository(),
+		Lists:        NewMemoryListRepository(movies),
+		History:      NewMemoryHistoryRepository(movies),
+		Interactions: NewMemoryInteractionRepository(),
+		Similarities: NewMemorySimilarityRepository(),
+		RerankRuns:   NewMemoryRerankRunRepository(),
+		Embeddings:   NewMemoryEmbeddingRepository(),
+	}
+}
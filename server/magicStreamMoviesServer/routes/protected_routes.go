@@ -1,16 +1,66 @@
 package routes
 
 import (
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/config"
 	controller "github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/controllers"
 	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/middleware"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupProtectedRoutes(router *gin.Engine) {
-	router.Use(middleware.AuthMiddleware())
+func SetupProtectedRoutes(router *gin.Engine, repos *repository.Repositories, services *Services, cfg *config.Config) {
+	router.Use(middleware.AuthMiddleware(cfg.Auth))
 
-	router.GET("/movie/:imdb_id", controller.GetMovie())
-	router.POST("/addmovie", controller.AddMovie())
-	router.GET("/recommendedmovies", controller.GetRecommendedMovies())
-	router.PATCH("/updatereview/:imdb_id", controller.AdminReviewUpdate())
+	router.GET("/movie/:imdb_id", services.Permissions.Require(middleware.PermMoviesRead), controller.GetMovie(repos.Movies))
+	router.POST("/addmovie", services.Permissions.Require(middleware.PermMoviesWrite), controller.AddMovie(repos.Movies, repos.Rankings, repos.Genres, services.SimilarIndex))
+	router.PUT("/movie/:imdb_id", services.Permissions.Require(middleware.PermMoviesWrite), controller.UpdateMovie(repos.Movies, repos.Genres, repos.MovieReferences(), services.SimilarIndex))
+	router.PATCH("/movie/:imdb_id", services.Permissions.Require(middleware.PermMoviesWrite), controller.PatchMovie(repos.Movies, repos.Genres, repos.MovieReferences(), services.SimilarIndex))
+	router.DELETE("/movie/:imdb_id", services.Permissions.Require(middleware.PermMoviesWrite), controller.DeleteMovie(repos.Movies, repos.MovieReferences(), services.SimilarIndex))
+	router.GET("/movie/:imdb_id/similar", services.Permissions.Require(middleware.PermMoviesRead), controller.GetSimilarMovies(services.SimilarIndex))
+	router.GET("/movie/:imdb_id/reviews", services.Permissions.Require(middleware.PermMoviesRead), controller.GetMovieReviews(repos.Reviews))
+	router.POST("/movie/:imdb_id/reviews", services.Permissions.Require(middleware.PermRatingsWrite), controller.CreateReview(repos.Movies, repos.Reviews, services.Recommender))
+	router.PUT("/movie/:imdb_id/reviews/me", services.Permissions.Require(middleware.PermRatingsWrite), controller.UpdateReview(repos.Movies, repos.Reviews, services.Recommender))
+	router.DELETE("/movie/:imdb_id/reviews/me", services.Permissions.Require(middleware.PermRatingsWrite), controller.DeleteReview(repos.Movies, repos.Reviews, services.Recommender))
+	router.GET("/recommendedmovies", services.Permissions.Require(middleware.PermMoviesRead), controller.GetRecommendedMovies(repos, int64(cfg.Recommender.MovieLimit)))
+	router.PATCH("/updatereview/:imdb_id", services.Permissions.Require(middleware.PermReviewsWrite), controller.AdminReviewUpdate(repos.Movies, services.RankingQueue, services.SimilarIndex))
+	router.GET("/jobs/:id", services.Permissions.Require(middleware.PermReviewsWrite), controller.GetJob(repos.Jobs))
+	router.POST("/genres", services.Permissions.Require(middleware.PermGenresWrite), controller.CreateGenre(repos.Genres))
+	router.PUT("/genres/:genre_id", services.Permissions.Require(middleware.PermGenresWrite), controller.UpdateGenre(repos.Genres, repos.Movies, repos.Users, services.SimilarIndex))
+	router.DELETE("/genres/:genre_id", services.Permissions.Require(middleware.PermGenresWrite), controller.DeleteGenre(repos.Genres, repos.Movies, repos.Users))
+	router.GET("/rankings", services.Permissions.Require(middleware.PermMoviesRead), controller.ListRankings(repos.Rankings))
+	router.POST("/rankings", services.Permissions.Require(middleware.PermRankingsWrite), controller.CreateRanking(repos.Rankings))
+	router.PUT("/rankings/order", services.Permissions.Require(middleware.PermRankingsWrite), controller.ReorderRankings(repos.Rankings, repos.Movies))
+	router.PUT("/rankings/:ranking_value", services.Permissions.Require(middleware.PermRankingsWrite), controller.UpdateRanking(repos.Rankings, repos.Movies))
+	router.DELETE("/rankings/:ranking_value", services.Permissions.Require(middleware.PermRankingsWrite), controller.DeleteRanking(repos.Rankings, repos.Movies))
+	router.POST("/rankings/rerank", services.Permissions.Require(middleware.PermReviewsWrite), controller.StartRerank(services.Reranker))
+	router.GET("/rankings/rerank/:id", services.Permissions.Require(middleware.PermReviewsWrite), controller.GetRerankRun(repos.RerankRuns))
+	router.POST("/logout", controller.Logout())
+	router.POST("/logout/all", controller.LogoutAll())
+
+	me := router.Group("/me", services.Permissions.Require(middleware.PermListsWrite))
+
+	me.GET("/watchlist", controller.GetList(repos.Lists, models.ListWatchlist))
+	me.POST("/watchlist", controller.AddToList(repos.Movies, repos.Lists, services.Recommender, models.ListWatchlist))
+	me.PUT("/watchlist/order", controller.ReorderWatchlist(repos.Lists))
+	me.DELETE("/watchlist/:imdb_id", controller.RemoveFromList(repos.Lists, services.Recommender, models.ListWatchlist))
+	me.GET("/favourites", controller.GetList(repos.Lists, models.ListFavourites))
+	me.POST("/favourites", controller.AddToList(repos.Movies, repos.Lists, services.Recommender, models.ListFavourites))
+	me.DELETE("/favourites/:imdb_id", controller.RemoveFromList(repos.Lists, services.Recommender, models.ListFavourites))
+
+	history := router.Group("/me", services.Permissions.Require(middleware.PermHistoryWrite))
+
+	history.POST("/history", controller.RecordPlayback(repos.Movies, repos.History, services.Recommender))
+	history.GET("/history", controller.GetWatchHistory(repos.History))
+	history.DELETE("/history/:imdb_id", controller.DeleteHistoryEntry(repos.History, services.Recommender))
+	history.GET("/continue-watching", controller.GetContinueWatching(repos.History))
+
+	admin := router.Group("/admin", services.Permissions.Require(middleware.PermUsersAdmin))
+
+	admin.GET("/users", controller.ListUsers(repos.Users))
+	admin.GET("/users/:user_id", controller.GetUser(repos.Users))
+	admin.PATCH("/users/:user_id/role", controller.UpdateUserRole(repos.Users))
+	admin.POST("/users/:user_id/disable", controller.DisableUser(repos.Users))
+	admin.POST("/users/:user_id/enable", controller.EnableUser(repos.Users))
+	admin.DELETE("/users/:user_id", controller.DeleteUser(repos.Users))
 }
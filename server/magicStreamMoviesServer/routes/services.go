@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/embedder"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/jobs"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/middleware"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/ranker"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/recommend"
+)
+
+// Services are the permissions, background workers and providers built from
+// the config in main and handed to the handlers. A nil worker or provider is
+// a disabled feature; its endpoints answer 503. Ping checks that the database
+// answers.
+type Services struct {
+	Ping         func(ctx context.Context) error
+	Permissions  middleware.RolePermissions
+	ReviewRanker ranker.ReviewRanker
+	RankingQueue *jobs.RankingQueue
+	Reranker     *jobs.Reranker
+	Recommender  *recommend.Engine
+	SimilarIndex *embedder.Index
+}
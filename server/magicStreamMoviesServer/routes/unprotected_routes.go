@@ -1,14 +1,20 @@
 package routes
 
 import (
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/config"
 	controller "github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/controllers"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/repository"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupUnProtectedRoutes(router *gin.Engine) {
+func SetupUnProtectedRoutes(router *gin.Engine, repos *repository.Repositories, services *Services, cfg *config.Config) {
 
-	router.GET("/movies", controller.GetMovies())
-	router.POST("/register", controller.RegisterUser())
-	router.POST("/login", controller.LoginUser())
-	
+	router.GET("/healthz", controller.HealthCheck())
+	router.GET("/readyz", controller.ReadinessCheck(services.Ping, repos.Rankings, services.ReviewRanker))
+	router.GET("/movies", controller.GetMovies(repos.Movies))
+	router.GET("/movies/search", controller.SearchMovies(repos.Movies))
+	router.GET("/genres", controller.GetGenres(repos.Genres))
+	router.POST("/register", controller.RegisterUser(repos.Users, repos.Genres))
+	router.POST("/login", controller.LoginUser(repos.Users, cfg.Auth))
+	router.POST("/refresh", controller.RefreshToken(cfg.Auth))
 }
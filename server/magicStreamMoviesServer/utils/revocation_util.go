@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/database"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func revokedTokenCollection() *mongo.Collection { return database.OpenCollection("revoked_tokens") }
+
+// refreshTokenLifetime is how long a refresh token, and so its token family,
+// stays usable.
+const refreshTokenLifetime = 24 * 7 * time.Hour
+
+// RevokeToken adds a single token (by jti) to the denylist until it expires
+func RevokeToken(tokenId, userId string, expiresAt time.Time) error {
+	if tokenId == "" {
+		return errors.New("token has no jti claim")
+	}
+
+	var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+	defer cancel()
+
+	_, err := revokedTokenCollection().InsertOne(ctx, models.RevokedToken{
+		TokenID:   tokenId,
+		UserID:    userId,
+		ExpiresAt: expiresAt,
+	})
+
+	return err
+}
+
+// RevokeAllUserTokens revokes every access and refresh token issued to the
+// user up to now by ending all of their token families, and clears the tokens
+// stored on the user document. Tokens from a later login belong to a new
+// family, so they are accepted even when issued in the same second.
+func RevokeAllUserTokens(userId string) error {
+	var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+	defer cancel()
+
+	if _, err := tokenFamilyCollection().DeleteMany(ctx, bson.M{"user_id": userId}); err != nil {
+		return err
+	}
+
+	return updateUserTokens(ctx, userId, "", "")
+}
+
+// IsTokenRevoked reports whether the token family the token described by
+// claims was issued in has ended, or whether the token itself is on the denylist.
+func IsTokenRevoked(claims *SignedDetails) (bool, error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if claims.FamilyId == "" {
+		// tokens without a family predate per-session revocation; reject them
+		return true, nil
+	}
+
+	families, err := tokenFamilyCollection().CountDocuments(ctx, bson.M{"family_id": claims.FamilyId, "user_id": claims.UserId}, options.Count().SetLimit(1))
+
+	if err != nil {
+		return false, err
+	}
+
+	if families == 0 {
+		return true, nil
+	}
+
+	if claims.ID == "" {
+		// tokens without jti predate revocation support; reject them
+		return true, nil
+	}
+
+	count, err := revokedTokenCollection().CountDocuments(ctx, bson.M{"jti": claims.ID}, options.Count().SetLimit(1))
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// GetTokenIdFromContext extracts the jti of the current access token stored in the Gin context
+func GetTokenIdFromContext(c *gin.Context) (string, error) {
+
+	tokenId, exists := c.Get("tokenId")
+
+	if !exists {
+		return "", errors.New("tokenId does not exists in this context")
+	}
+
+	id, ok := tokenId.(string)
+
+	if !ok {
+		return "", errors.New("unable to retrieve tokenId")
+	}
+
+	return id, nil
+}
+
+// GetTokenFamilyIdFromContext extracts the token family of the current access token stored in the Gin context
+func GetTokenFamilyIdFromContext(c *gin.Context) (string, error) {
+
+	familyId, exists := c.Get("tokenFamilyId")
+
+	if !exists {
+		return "", errors.New("tokenFamilyId does not exists in this context")
+	}
+
+	id, ok := familyId.(string)
+
+	if !ok {
+		return "", errors.New("unable to retrieve tokenFamilyId")
+	}
+
+	return id, nil
+}
+
+// GetTokenExpiryFromContext extracts the expiry of the current access token stored in the Gin context
+func GetTokenExpiryFromContext(c *gin.Context) (time.Time, error) {
+
+	expiresAt, exists := c.Get("tokenExpiresAt")
+
+	if !exists {
+		return time.Time{}, errors.New("tokenExpiresAt does not exists in this context")
+	}
+
+	t, ok := expiresAt.(time.Time)
+
+	if !ok {
+		return time.Time{}, errors.New("unable to retrieve tokenExpiresAt")
+	}
+
+	return t, nil
+}
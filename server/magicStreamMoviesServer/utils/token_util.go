@@ -2,15 +2,18 @@ package utils
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
-	"os"
 	"time"
 
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/config"
 	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/database"
+	"github.com/JabelResendiz/MagicStream/server/magicStreamMoviesServer/models"
 	"github.com/gin-gonic/gin"
 	jwt "github.com/golang-jwt/jwt/v5"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type SignedDetails struct {
@@ -19,21 +22,44 @@ type SignedDetails struct {
 	LastName  string
 	Role      string
 	UserId    string
+	// FamilyId identifies the login session the token belongs to; it is kept
+	// across refreshes so rotation and revocation stay within one device.
+	FamilyId string
 	jwt.RegisteredClaims
 }
 
-var userCollection *mongo.Collection = database.OpenCollection("users")
-var SECRET_KEY string = os.Getenv("SECRET_KEY")
-var SECRET_REFRESH_KEY string = os.Getenv("SECRET_REFRESH_KEY")
+func userCollection() *mongo.Collection { return database.OpenCollection("users") }
 
-func GenerateAllTokens(email, firstName, lastName, role, userId string) (string, string, error) {
+func tokenFamilyCollection() *mongo.Collection { return database.OpenCollection("token_families") }
+
+// ErrRefreshTokenReused is returned when a refresh token that has already been
+// rotated out is presented again.
+var ErrRefreshTokenReused = errors.New("refresh token has already been used")
+
+// ErrAccountDisabled is returned when tokens are requested for a disabled account.
+var ErrAccountDisabled = errors.New("account is disabled")
+
+// ErrTokenFamilyRevoked is returned when the session of a refresh token has
+// been logged out, revoked or has expired.
+var ErrTokenFamilyRevoked = errors.New("refresh token session has been revoked")
+
+// NewTokenFamilyId returns the id of a new login session
+func NewTokenFamilyId() string {
+	return bson.NewObjectID().Hex()
+}
+
+// GenerateAllTokens signs an access token with auth.SecretKey and a refresh
+// token with auth.SecretRefreshKey
+func GenerateAllTokens(auth config.AuthConfig, email, firstName, lastName, role, userId, familyId string) (string, string, error) {
 	claims := &SignedDetails{
 		Email:     email,
 		FirstName: firstName,
 		LastName:  lastName,
 		Role:      role,
 		UserId:    userId,
+		FamilyId:  familyId,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        bson.NewObjectID().Hex(),
 			Issuer:    "MagicStream",
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
@@ -42,7 +68,7 @@ func GenerateAllTokens(email, firstName, lastName, role, userId string) (string,
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	signedToken, err := token.SignedString([]byte(SECRET_KEY))
+	signedToken, err := token.SignedString([]byte(auth.SecretKey))
 
 	if err != nil {
 		return "", "", err
@@ -54,15 +80,17 @@ func GenerateAllTokens(email, firstName, lastName, role, userId string) (string,
 		LastName:  lastName,
 		Role:      role,
 		UserId:    userId,
+		FamilyId:  familyId,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        bson.NewObjectID().Hex(),
 			Issuer:    "MagicStream",
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * 7 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(refreshTokenLifetime)),
 		},
 	}
 
 	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	signedRefreshToken, err := refreshToken.SignedString([]byte(SECRET_REFRESH_KEY))
+	signedRefreshToken, err := refreshToken.SignedString([]byte(auth.SecretRefreshKey))
 
 	if err != nil {
 		return "", "", err
@@ -72,10 +100,37 @@ func GenerateAllTokens(email, firstName, lastName, role, userId string) (string,
 
 }
 
-func UpdateAllTokens(userId, token, refreshToken string) (err error) {
+// UpdateAllTokens stores the tokens issued by a login: the refresh token
+// becomes the current one of its family and both are kept on the user.
+func UpdateAllTokens(userId, familyId, token, refreshToken string) (err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
 	defer cancel()
 
+	now := time.Now()
+
+	_, err = tokenFamilyCollection().UpdateOne(ctx,
+		bson.M{"family_id": familyId},
+		bson.M{
+			"$set": bson.M{
+				"user_id":       userId,
+				"refresh_token": refreshToken,
+				"updated_at":    now,
+				"expires_at":    now.Add(refreshTokenLifetime),
+			},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.UpdateOne().SetUpsert(true),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return updateUserTokens(ctx, userId, token, refreshToken)
+}
+
+// updateUserTokens keeps the last issued tokens on the user document
+func updateUserTokens(ctx context.Context, userId, token, refreshToken string) error {
 	updatedAt, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 
 	updateData := bson.M{
@@ -86,13 +141,9 @@ func UpdateAllTokens(userId, token, refreshToken string) (err error) {
 		},
 	}
 
-	_, err = userCollection.UpdateOne(ctx, bson.M{"user_id": userId}, updateData)
-
-	if err != nil {
-		return err
-	}
+	_, err := userCollection().UpdateOne(ctx, bson.M{"user_id": userId}, updateData)
 
-	return nil
+	return err
 }
 
 func GetAccessToken(c *gin.Context) (string, error) {
@@ -112,28 +163,154 @@ func GetAccessToken(c *gin.Context) (string, error) {
 	return tokenString, nil
 }
 
-func ValidateToken(tokenString string) (*SignedDetails, error) {
+// ValidateToken checks the signature and expiry of an access token signed
+// with auth.SecretKey.
+func ValidateToken(auth config.AuthConfig, tokenString string) (*SignedDetails, error) {
+	return parseToken(tokenString, auth.SecretKey)
+}
+
+// ValidateRefreshToken checks the signature and expiry of a refresh token
+// signed with auth.SecretRefreshKey.
+func ValidateRefreshToken(auth config.AuthConfig, tokenString string) (*SignedDetails, error) {
+	return parseToken(tokenString, auth.SecretRefreshKey)
+}
+
+// parseToken verifies tokenString with the given HMAC key and returns its claims
+func parseToken(tokenString, key string) (*SignedDetails, error) {
 	claims := &SignedDetails{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SECRET_KEY), nil
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(key), nil
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-		return nil, err
+	if !token.Valid {
+		return nil, errors.New("invalid token")
 	}
 
-	if claims.ExpiresAt.Time.Before(time.Now()) {
+	if claims.ExpiresAt == nil || claims.ExpiresAt.Time.Before(time.Now()) {
 		return nil, errors.New("token has expired")
 	}
 
 	return claims, nil
 }
 
+// RotateRefreshToken exchanges a refresh token for a new access/refresh pair
+// within the same token family. The presented token must be the current one of
+// its family; a validly signed token that no longer matches has already been
+// rotated, so that family is revoked and ErrRefreshTokenReused is returned.
+// Other sessions of the user are left untouched.
+func RotateRefreshToken(auth config.AuthConfig, refreshToken string) (*SignedDetails, string, string, error) {
+	claims, err := ValidateRefreshToken(auth, refreshToken)
+
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if claims.FamilyId == "" {
+		// tokens without a family predate per-session rotation
+		return nil, "", "", ErrTokenFamilyRevoked
+	}
+
+	var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+	defer cancel()
+
+	var family models.TokenFamily
+
+	err = tokenFamilyCollection().FindOne(ctx, bson.M{"family_id": claims.FamilyId, "user_id": claims.UserId}).Decode(&family)
+
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, "", "", ErrTokenFamilyRevoked
+		}
+		return nil, "", "", err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(family.RefreshToken), []byte(refreshToken)) != 1 {
+		if err := RevokeTokenFamily(claims.UserId, claims.FamilyId); err != nil {
+			return nil, "", "", err
+		}
+		return nil, "", "", ErrRefreshTokenReused
+	}
+
+	var stored struct {
+		Email     string `bson:"email"`
+		FirstName string `bson:"first_name"`
+		LastName  string `bson:"last_name"`
+		Role      string `bson:"role"`
+		Disabled  bool   `bson:"disabled"`
+	}
+
+	err = userCollection().FindOne(ctx, bson.M{"user_id": claims.UserId}).Decode(&stored)
+
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if stored.Disabled {
+		return nil, "", "", ErrAccountDisabled
+	}
+
+	// Re-read the profile from the database so role changes take effect on refresh
+	token, newRefreshToken, err := GenerateAllTokens(auth, stored.Email, stored.FirstName, stored.LastName, stored.Role, claims.UserId, claims.FamilyId)
+
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	// Only swap the token if the family still holds the one we validated, so
+	// two concurrent refreshes of the same session cannot both succeed.
+	now := time.Now()
+
+	result, err := tokenFamilyCollection().UpdateOne(ctx,
+		bson.M{"family_id": claims.FamilyId, "refresh_token": refreshToken},
+		bson.M{"$set": bson.M{
+			"refresh_token": newRefreshToken,
+			"updated_at":    now,
+			"expires_at":    now.Add(refreshTokenLifetime),
+		}},
+	)
+
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if result.MatchedCount == 0 {
+		if err := RevokeTokenFamily(claims.UserId, claims.FamilyId); err != nil {
+			return nil, "", "", err
+		}
+		return nil, "", "", ErrRefreshTokenReused
+	}
+
+	if err := updateUserTokens(ctx, claims.UserId, token, newRefreshToken); err != nil {
+		return nil, "", "", err
+	}
+
+	claims.Email = stored.Email
+	claims.FirstName = stored.FirstName
+	claims.LastName = stored.LastName
+	claims.Role = stored.Role
+
+	return claims, token, newRefreshToken, nil
+}
+
+// RevokeTokenFamily ends one login session: its refresh token can no longer be
+// rotated and the access tokens issued within it stop being accepted.
+func RevokeTokenFamily(userId, familyId string) error {
+	var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+	defer cancel()
+
+	_, err := tokenFamilyCollection().DeleteOne(ctx, bson.M{"family_id": familyId, "user_id": userId})
+
+	return err
+}
+
 // extracts the userId value stored in the Gin context
 func GetUserIdFromContext(c *gin.Context) (string, error) {
 